@@ -1,32 +1,44 @@
 package sqlParser
 
-
 type ParsedStmtInterface interface {
-	GetQueryType() QueryType                  // GetQueryType returns the type of the query (e.g., SELECT, INSERT, UPDATE, DELETE)
-	GetTables() []string                      // GetTables returns the tables involved in the statement
-	GetColumns() []string                     // GetColumns returns the columns referenced in the statement
+	GetQueryType() QueryType      // GetQueryType returns the type of the query (e.g., SELECT, INSERT, UPDATE, DELETE)
+	GetTables() []string          // GetTables returns the tables involved in the statement
+	GetColumns() []string         // GetColumns returns the columns referenced in the statement
 	GetConditions() []interface{} // GetConditionsAndOperators returns the conditions and operators specified in the statement
-	GetValues() []string                      // GetValues returns the values specified in the statement
+	GetValues() []string          // GetValues returns the values specified in the statement
+	GetStatement() Statement      // GetStatement returns the AST of the statement
+	GetParams() []ParamInfo       // GetParams returns the bind parameters of the statement
+	GetResult() []ResultColumn    // GetResult returns the columns of the result set of the statement
 }
 
-
-
-func (stmt *ParsedStmt ) GetQueryType() QueryType {
+func (stmt *ParsedStmt) GetQueryType() QueryType {
 	return stmt.QueryType
 }
 
-func (stmt *ParsedStmt ) GetTables() []string {
+func (stmt *ParsedStmt) GetTables() []string {
 	return stmt.Tables
 }
 
-func (stmt *ParsedStmt ) GetColumns() []string {
+func (stmt *ParsedStmt) GetColumns() []string {
 	return stmt.Columns
 }
 
-func (stmt *ParsedStmt ) GetConditions() []interface{} {
+func (stmt *ParsedStmt) GetConditions() []interface{} {
 	return stmt.Conditions
 }
 
-func (stmt *ParsedStmt ) GetValues() []string {
+func (stmt *ParsedStmt) GetValues() []string {
 	return stmt.Values
-}
\ No newline at end of file
+}
+
+func (stmt *ParsedStmt) GetStatement() Statement {
+	return stmt.Stmt
+}
+
+func (stmt *ParsedStmt) GetParams() []ParamInfo {
+	return stmt.Params
+}
+
+func (stmt *ParsedStmt) GetResult() []ResultColumn {
+	return stmt.Result
+}
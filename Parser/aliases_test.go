@@ -0,0 +1,69 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectListAliases(t *testing.T) {
+	tests := []struct {
+		name   string
+		sql    string
+		result []ResultColumn
+		err    string
+	}{
+		{
+			name: "aliases with and without AS",
+			sql:  "SELECT age + 1 AS next, name n FROM users",
+			result: []ResultColumn{
+				{Name: "NEXT", Type: IntegerType},
+				{Name: "N", Type: TextType, Table: "USERS", Column: "NAME"},
+			},
+		},
+		{
+			name:   "quoted alias",
+			sql:    `SELECT age AS "Next Age" FROM users`,
+			result: []ResultColumn{{Name: "Next Age", Type: IntegerType, Table: "USERS", Column: "AGE"}},
+		},
+		{
+			name: "unaliased expressions",
+			sql:  "SELECT 1 + 2, UPPER(name) FROM users",
+			result: []ResultColumn{
+				{Name: "?column?", Type: IntegerType},
+				{Name: "UPPER", Type: TextType},
+			},
+		},
+		{
+			name: "ordered by alias and position",
+			sql:  "SELECT age + 1 AS next, name FROM users ORDER BY next DESC, 2",
+			result: []ResultColumn{
+				{Name: "NEXT", Type: IntegerType},
+				{Name: "NAME", Type: TextType, Table: "USERS", Column: "NAME"},
+			},
+		},
+		{name: "alias in where", sql: "SELECT age AS a FROM users WHERE a > 1", err: "column A does not exist in table USERS"},
+		{name: "ambiguous alias", sql: "SELECT age AS a, name AS a FROM users ORDER BY a", err: "ORDER BY A is ambiguous"},
+		{name: "position out of the list", sql: "SELECT age FROM users ORDER BY 3", err: "ORDER BY position 3 is not in select list"},
+		{name: "keyword alias", sql: "SELECT age AS from FROM users", err: `expected an alias but found "FROM" at position 14`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsedStmt, err := newTestParser().ParseSQL(test.sql)
+			checkError(t, err, test.err)
+			if test.err == "" && !reflect.DeepEqual(parsedStmt.Result, test.result) {
+				t.Fatalf("expected the result %+v, got %+v", test.result, parsedStmt.Result)
+			}
+		})
+	}
+}
+
+func TestSelectItemString(t *testing.T) {
+	parsedStmt := mustParse(t, newTestParser(), "SELECT age + 1 next, name FROM users")
+	var items []string
+	for _, item := range parsedStmt.Stmt.(*SelectStmt).Columns {
+		items = append(items, item.String())
+	}
+	if want := []string{"AGE + 1 AS NEXT", "NAME"}; !reflect.DeepEqual(items, want) {
+		t.Fatalf("expected the select list %q, got %q", want, items)
+	}
+}
@@ -0,0 +1,81 @@
+package sqlParser
+
+import "fmt"
+
+// CaseExpr is a CASE expression. The simple form compares the operand to the
+// WHEN values, the searched form, without operand, tests the WHEN conditions.
+// CASE [operand] WHEN value_or_condition THEN result ... [ELSE result] END
+type CaseExpr struct {
+	Trivia
+	Operand Expr // nil in the searched form
+	Whens   []WhenClause
+	Else    Expr // nil without ELSE
+}
+
+// WhenClause is a WHEN ... THEN ... branch of a CASE expression.
+type WhenClause struct {
+	When Expr
+	Then Expr
+}
+
+func (expr *CaseExpr) String() string {
+	str := "CASE"
+	if expr.Operand != nil {
+		str += " " + expr.Operand.String()
+	}
+	for _, when := range expr.Whens {
+		str += " WHEN " + when.When.String() + " THEN " + when.Then.String()
+	}
+	if expr.Else != nil {
+		str += " ELSE " + expr.Else.String()
+	}
+	return str + " END"
+}
+
+func (*CaseExpr) exprNode() {}
+
+// caseType checks the branches of a CASE expression and returns the type
+// their results convert to.
+func (parser *SQLParser) caseType(expr *CaseExpr, scope exprScope) (DataType, error) {
+	results := make([]Expr, 0, len(expr.Whens)+1)
+	for _, when := range expr.Whens {
+		var err error
+		if expr.Operand != nil {
+			err = parser.checkComparable(expr.Operand, when.When, "CASE", scope)
+		} else {
+			err = parser.checkBoolean(when.When, "WHEN", scope)
+		}
+		if err != nil {
+			return UnknownType, err
+		}
+		results = append(results, when.Then)
+	}
+	if expr.Else != nil {
+		results = append(results, expr.Else)
+	}
+
+	common := UnknownType
+	var params []*Param
+	for _, result := range results {
+		if param, ok := result.(*Param); ok {
+			params = append(params, param)
+			continue
+		}
+		typ, err := parser.exprType(result, scope)
+		if err != nil {
+			return UnknownType, err
+		}
+		next, ok := commonType(common, typ)
+		if !ok {
+			return UnknownType, fmt.Errorf("CASE types %s and %s can not be matched, in %s", common, typ, result)
+		}
+		common = next
+	}
+	// the parameters returned by a branch take the type of the other branches
+	for _, param := range params {
+		if err := scope.params.add(param, "", "", common); err != nil {
+			return UnknownType, err
+		}
+	}
+	return common, nil
+}
@@ -0,0 +1,41 @@
+package sqlParser
+
+import "testing"
+
+func TestCase(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		typ  DataType // the type of the first result column
+		err  string
+	}{
+		{name: "searched", sql: "SELECT CASE WHEN age < 18 THEN 'minor' WHEN age < 65 THEN 'adult' ELSE 'senior' END FROM users", typ: TextType},
+		{name: "simple", sql: "SELECT CASE age WHEN 1 THEN 1.5 WHEN 2 THEN 2 END FROM users", typ: DecimalType},
+		{name: "null branch", sql: "SELECT CASE WHEN email IS NULL THEN NULL ELSE age END FROM users", typ: IntegerType},
+		{name: "parameter branch", sql: "SELECT CASE WHEN age > 1 THEN ? ELSE name END FROM users", typ: TextType},
+		{name: "in a condition", sql: "SELECT id FROM users WHERE CASE WHEN age > 18 THEN TRUE ELSE FALSE END"},
+		{name: "in an update", sql: "UPDATE users SET age = CASE WHEN age < 0 THEN 0 ELSE age END WHERE id = 1"},
+		{name: "mismatched results", sql: "SELECT CASE WHEN age > 1 THEN 'a' ELSE 1 END FROM users", err: "CASE types TEXT and INTEGER can not be matched, in 1"},
+		{name: "condition type", sql: "SELECT CASE WHEN age THEN 1 END FROM users", err: "argument of WHEN must be BOOLEAN, not AGE of type INTEGER"},
+		{name: "operand type", sql: "SELECT CASE age WHEN 'a' THEN 1 END FROM users", err: "CASE can not compare AGE of type INTEGER with 'a' of type TEXT"},
+		{name: "without when", sql: "SELECT CASE age END FROM users", err: "expected WHEN"},
+		{name: "without end", sql: "SELECT CASE WHEN age > 1 THEN 1 FROM users", err: "syntax error"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsedStmt, err := newTestParser().ParseSQL(test.sql)
+			checkError(t, err, test.err)
+			if test.err == "" && test.typ != "" && parsedStmt.Result[0].Type != test.typ {
+				t.Fatalf("expected a %s result, got %s", test.typ, parsedStmt.Result[0].Type)
+			}
+		})
+	}
+}
+
+func TestCaseString(t *testing.T) {
+	parsedStmt := mustParse(t, newTestParser(), "SELECT case age when 1 then 'one' else 'many' end AS n FROM users")
+	expr := parsedStmt.Stmt.(*SelectStmt).Columns[0].Expr.(*CaseExpr)
+	if got, want := expr.String(), "CASE AGE WHEN 1 THEN 'one' ELSE 'many' END"; got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,154 @@
+package sqlParser
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CreateSchema represents a CREATE SCHEMA or CREATE DATABASE statement.
+type CreateSchema struct {
+	Trivia
+	Name        Identifier
+	IfNotExists bool
+}
+
+// DropSchema represents a DROP SCHEMA or DROP DATABASE statement.
+type DropSchema struct {
+	Trivia
+	Name     Identifier
+	IfExists bool
+	Cascade  bool // the tables and views of the schema are dropped with it
+}
+
+// UseStmt represents a USE statement, which makes a schema the current one.
+type UseStmt struct {
+	Trivia
+	Schema Identifier
+}
+
+// SetStmt represents a SET statement of a session setting. Only the
+// search_path setting is supported.
+type SetStmt struct {
+	Trivia
+	Variable Identifier
+	Values   []Identifier // empty for SET ... TO DEFAULT
+}
+
+func (stmt *CreateSchema) QueryType() QueryType { return CreateQuery }
+func (stmt *DropSchema) QueryType() QueryType   { return DropQuery }
+func (stmt *UseStmt) QueryType() QueryType      { return UseQuery }
+func (stmt *SetStmt) QueryType() QueryType      { return SetQuery }
+
+// validateCreateSchema checks that the schema does not exist yet, unless
+// IF NOT EXISTS is given.
+func (parser *SQLParser) validateCreateSchema(stmt *CreateSchema) error {
+	name, err := parser.Schema.LookupSchema(stmt.Name)
+	if err != nil || stmt.IfNotExists {
+		return nil
+	}
+	return fmt.Errorf("schema %s already exists", name)
+}
+
+// validateDropSchema checks that the schema exists, unless IF EXISTS is
+// given, and can be dropped, and returns its name. The default schema
+// can not be dropped, a schema holding tables or views only with CASCADE,
+// and not when a view of another schema reads them.
+func (parser *SQLParser) validateDropSchema(stmt *DropSchema) (string, error) {
+	name, err := parser.Schema.LookupSchema(stmt.Name)
+	switch {
+	case err != nil && stmt.IfExists:
+		return "", nil
+	case err != nil:
+		return "", err
+	case name == parser.Schema.Name:
+		return "", fmt.Errorf("can not drop schema %s, it is the default schema", name)
+	}
+
+	dropped := parser.Schema.child(name)
+	if len(dropped.Tables) == 0 && len(dropped.Views) == 0 {
+		return name, nil
+	}
+	if !stmt.Cascade {
+		return "", fmt.Errorf("can not drop schema %s because it is not empty", name)
+	}
+	for _, view := range parser.Schema.views() {
+		if schemaName, _, _ := parser.Schema.namespace(view.Name); schemaName == name {
+			continue
+		}
+		for _, table := range view.Tables {
+			if schemaName, _, _ := parser.Schema.namespace(table); schemaName == name {
+				return "", fmt.Errorf("can not drop schema %s because view %s depends on it", name, view.Name)
+			}
+		}
+	}
+	return name, nil
+}
+
+// validateSet checks the setting of a SET statement and returns its values.
+// The schemas of the search path must exist.
+func (parser *SQLParser) validateSet(stmt *SetStmt) ([]string, error) {
+	if !stmt.Variable.Matches("SEARCH_PATH") {
+		return nil, fmt.Errorf("setting %s does not exist", stmt.Variable)
+	}
+	var schemas []string
+	for _, value := range stmt.Values {
+		name, err := parser.Schema.LookupSchema(value)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, name)
+	}
+	if valid, name := containsNoDuplicates(schemas); !valid {
+		return nil, fmt.Errorf("schema %s is specified more than once in the search path", name)
+	}
+	return schemas, nil
+}
+
+// createSchema adds an empty schema to the database.
+func (schema *Schema) createSchema(name string) {
+	if schema.Schemas == nil {
+		schema.Schemas = map[string]*Schema{}
+	}
+	schema.Schemas[name] = &Schema{Name: name}
+}
+
+// schemaTables returns the tables and views of the schema, named as by
+// LookupTable and sorted.
+func (schema *Schema) schemaTables(name string) []string {
+	namespace := schema.child(name)
+	if namespace == nil {
+		return nil
+	}
+	var tables []string
+	for _, table := range append(namespace.GetSchemaTables(), namespace.GetSchemaViews()...) {
+		tables = append(tables, schema.qualify(name, table))
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// dropSchema removes the schema from the database, and from the current
+// schema and search path.
+func (schema *Schema) dropSchema(name string) {
+	delete(schema.Schemas, name)
+	if schema.Current == name {
+		schema.Current = ""
+	}
+	path := schema.SearchPath[:0]
+	for _, other := range schema.SearchPath {
+		if other != name {
+			path = append(path, other)
+		}
+	}
+	schema.SearchPath = path
+}
+
+// setSearchPath makes the first schema of the path the current one, the
+// others being searched after it. An empty path restores the default
+// schema.
+func (schema *Schema) setSearchPath(path []string) {
+	schema.Current, schema.SearchPath = "", nil
+	if len(path) > 0 {
+		schema.Current, schema.SearchPath = path[0], path[1:]
+	}
+}
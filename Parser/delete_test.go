@@ -0,0 +1,70 @@
+package sqlParser
+
+import "testing"
+
+func TestDelete(t *testing.T) {
+	runParseTests(t, newTestParser, []parseTest{
+		{name: "where", sql: "DELETE FROM users WHERE age < 18"},
+		{name: "without where", sql: "DELETE FROM users"},
+		{name: "using", sql: "DELETE FROM orders USING users WHERE orders.user_id = users.id AND users.age > 90"},
+		{name: "unknown table", sql: "DELETE FROM nope WHERE id = 1", err: "table NOPE does not exist in the schema"},
+		{name: "unknown using table", sql: "DELETE FROM orders USING nope WHERE id = 1", err: "table NOPE does not exist in the schema"},
+		{name: "order by", sql: "DELETE FROM users ORDER BY id LIMIT 1", err: "generic does not support ORDER BY in DELETE statements"},
+	})
+	runParseTests(t, newDialectParser(MySQLDialect), []parseTest{
+		{name: "order by and limit", sql: "DELETE FROM users WHERE age > 1 ORDER BY age LIMIT 5"},
+	})
+	runParseTests(t, newDialectParser(SQLServerDialect), []parseTest{
+		{name: "using", sql: "DELETE FROM orders USING users WHERE orders.user_id = users.id", err: "sqlserver does not support USING"},
+	})
+}
+
+func TestSafeMode(t *testing.T) {
+	safe := func() *SQLParser {
+		parser := newTestParser()
+		parser.SafeMode = true
+		return parser
+	}
+	runParseTests(t, safe, []parseTest{
+		{name: "delete with where", sql: "DELETE FROM users WHERE id = 1"},
+		{name: "update with where", sql: "UPDATE users SET age = 1 WHERE id = 1"},
+		{name: "select", sql: "SELECT id FROM users"},
+		{name: "delete without where", sql: "DELETE FROM users", err: "safe mode: DELETE without a WHERE clause reading a column deletes every row of table USERS"},
+		{name: "update without where", sql: "UPDATE users SET age = 1", err: "safe mode: UPDATE without a WHERE clause reading a column changes every row of table USERS"},
+		{name: "where reading no column", sql: "DELETE FROM users WHERE 1 = 1", err: "safe mode: DELETE"},
+		{name: "where true", sql: "UPDATE users SET age = 1 WHERE TRUE", err: "safe mode: UPDATE"},
+	})
+
+	parser := safe()
+	parser.AllowFullTableWrites = true
+	mustParse(t, parser, "DELETE FROM users")
+	mustParse(t, parser, "UPDATE users SET age = 1 WHERE 1 = 1")
+}
+
+func TestIsDestructiveWrite(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want bool
+	}{
+		{"DELETE FROM users", true},
+		{"DELETE FROM users WHERE TRUE", true},
+		{"DELETE FROM users WHERE id = 1", false},
+		{"UPDATE users SET age = 1", true},
+		{"UPDATE users SET age = 1 WHERE age > 1", false},
+		{"SELECT id FROM users", false},
+		{"INSERT INTO users (id) VALUES (1)", false},
+	}
+	for _, test := range tests {
+		parsedStmt := mustParse(t, newTestParser(), test.sql)
+		if got := IsDestructiveWrite(parsedStmt.Stmt); got != test.want {
+			t.Errorf("%s: expected IsDestructiveWrite to be %v, got %v", test.sql, test.want, got)
+		}
+	}
+
+	// a LIMIT bounds the rows written
+	parser := newTestParser()
+	parser.Dialect = MySQLDialect
+	if parsedStmt := mustParse(t, parser, "DELETE FROM users LIMIT 10"); IsDestructiveWrite(parsedStmt.Stmt) {
+		t.Error("expected a DELETE with a LIMIT not to be destructive")
+	}
+}
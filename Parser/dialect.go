@@ -0,0 +1,87 @@
+package sqlParser
+
+// Dialect describes the SQL flavour accepted by the parser. The lexical and
+// grammatical rules that differ between database engines are switched on and
+// off through its fields.
+type Dialect struct {
+	Name             string
+	IdentifierQuotes string // opening quotes of the quoted identifiers: ", ` or [
+	NestedComments   bool   // block comments may be nested: /* a /* b */ c */
+	BackslashEscapes bool   // backslash escapes are allowed in every string, not only in E'...'
+	DollarQuotes     bool   // $$...$$ and $tag$...$tag$ strings, whose body is taken as written
+	UpdateFrom       bool   // UPDATE ... FROM joins other tables to the updated one
+	MultiTableUpdate bool   // UPDATE t1, t2 SET ... updates several tables
+	DeleteUsing      bool   // DELETE ... USING joins other tables to the one rows are deleted from
+	WriteOrderLimit  bool   // UPDATE and DELETE take ORDER BY and LIMIT
+	DistinctOn       bool   // SELECT DISTINCT ON (expr, ...) keeps the first row of each group
+	OnConflict       bool   // INSERT ... ON CONFLICT upserts
+	OnDuplicateKey   bool   // INSERT ... ON DUPLICATE KEY UPDATE upserts
+}
+
+var (
+	// GenericDialect follows the SQL standard, it is used when no dialect is set.
+	GenericDialect = Dialect{
+		Name:             "generic",
+		IdentifierQuotes: `"`,
+		NestedComments:   true,
+		DollarQuotes:     true,
+		UpdateFrom:       true,
+		DeleteUsing:      true,
+		DistinctOn:       true,
+		OnConflict:       true,
+		OnDuplicateKey:   true,
+	}
+
+	PostgresDialect = Dialect{
+		Name:             "postgres",
+		IdentifierQuotes: `"`,
+		NestedComments:   true,
+		DollarQuotes:     true,
+		UpdateFrom:       true,
+		DeleteUsing:      true,
+		DistinctOn:       true,
+		OnConflict:       true,
+	}
+
+	MySQLDialect = Dialect{
+		Name:             "mysql",
+		IdentifierQuotes: "`",
+		BackslashEscapes: true,
+		MultiTableUpdate: true,
+		WriteOrderLimit:  true,
+		DeleteUsing:      true,
+		OnDuplicateKey:   true,
+	}
+
+	SQLiteDialect = Dialect{
+		Name:             "sqlite",
+		IdentifierQuotes: "\"`[",
+		UpdateFrom:       true,
+		WriteOrderLimit:  true,
+		OnConflict:       true,
+	}
+
+	SQLServerDialect = Dialect{
+		Name:             "sqlserver",
+		IdentifierQuotes: `"[`,
+		NestedComments:   true,
+		UpdateFrom:       true,
+	}
+)
+
+// dialect returns the dialect of the parser, falling back to GenericDialect
+// for parsers built without NewSQLParser.
+func (parser *SQLParser) dialect() Dialect {
+	if parser.Dialect.Name == "" {
+		return GenericDialect
+	}
+	return parser.Dialect
+}
+
+// closingQuote returns the quote closing a quoted identifier.
+func closingQuote(open rune) rune {
+	if open == '[' {
+		return ']'
+	}
+	return open
+}
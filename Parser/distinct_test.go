@@ -0,0 +1,34 @@
+package sqlParser
+
+import "testing"
+
+func TestDistinct(t *testing.T) {
+	runParseTests(t, newTestParser, []parseTest{
+		{name: "distinct", sql: "SELECT DISTINCT name, age FROM users"},
+		{name: "all", sql: "SELECT ALL name FROM users"},
+		{name: "distinct star", sql: "SELECT DISTINCT * FROM users"},
+		{name: "distinct on", sql: "SELECT DISTINCT ON (user_id) user_id, total FROM orders ORDER BY user_id, created_at DESC"},
+		{name: "distinct on without order by", sql: "SELECT DISTINCT ON (user_id, id) total FROM orders"},
+		{name: "distinct on a position", sql: "SELECT DISTINCT ON (1) user_id, total FROM orders ORDER BY user_id"},
+		{name: "distinct on an alias", sql: "SELECT DISTINCT ON (buyer) user_id AS buyer FROM orders ORDER BY buyer"},
+		{name: "distinct on unknown column", sql: "SELECT DISTINCT ON (nope) id FROM orders", err: "column NOPE does not exist in table ORDERS"},
+		{name: "order by not leading", sql: "SELECT DISTINCT ON (user_id) total FROM orders ORDER BY created_at", err: "SELECT DISTINCT ON expressions must match the leading ORDER BY expressions"},
+		{name: "distinct on position out of the list", sql: "SELECT DISTINCT ON (3) user_id FROM orders", err: "DISTINCT ON position 3 is not in select list"},
+		{name: "distinct and all", sql: "SELECT DISTINCT ALL name FROM users", err: "column ALL does not exist in table USERS"},
+	})
+	runParseTests(t, newDialectParser(MySQLDialect), []parseTest{
+		{name: "distinct", sql: "SELECT DISTINCT name FROM users"},
+		{name: "distinct on", sql: "SELECT DISTINCT ON (name) name FROM users", err: "mysql does not support DISTINCT ON"},
+	})
+}
+
+func TestDistinctStatement(t *testing.T) {
+	parsedStmt := mustParse(t, newTestParser(), "SELECT DISTINCT ON (user_id) user_id, total FROM orders ORDER BY user_id")
+	stmt := parsedStmt.Stmt.(*SelectStmt)
+	if !stmt.Distinct || len(stmt.DistinctOn) != 1 || stmt.DistinctOn[0].String() != "USER_ID" {
+		t.Fatalf("unexpected DISTINCT ON: %v %v", stmt.Distinct, stmt.DistinctOn)
+	}
+	if stmt = mustParse(t, newTestParser(), "SELECT ALL name FROM users").Stmt.(*SelectStmt); stmt.Distinct || !stmt.All {
+		t.Fatalf("unexpected ALL: %v %v", stmt.Distinct, stmt.All)
+	}
+}
@@ -0,0 +1,90 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	runParseTests(t, newTestParser, []parseTest{
+		{name: "select", sql: "EXPLAIN SELECT id FROM users WHERE age > 18"},
+		{name: "insert", sql: "EXPLAIN INSERT INTO users (id, name) VALUES (1, 'ada')"},
+		{name: "update", sql: "EXPLAIN UPDATE users SET age = 1 WHERE id = 1"},
+		{name: "delete", sql: "EXPLAIN DELETE FROM orders WHERE total < 1"},
+		{name: "options", sql: "EXPLAIN ANALYZE VERBOSE FORMAT JSON SELECT id FROM users"},
+		{name: "format", sql: "EXPLAIN FORMAT YAML DELETE FROM orders WHERE id = 1"},
+		{name: "unknown format", sql: "EXPLAIN FORMAT CSV SELECT id FROM users", err: "TEXT, JSON, XML or YAML"},
+		{name: "transaction", sql: "EXPLAIN BEGIN"},
+		{name: "create", sql: "EXPLAIN CREATE VIEW v AS SELECT id FROM users"},
+		{name: "truncate", sql: "EXPLAIN TRUNCATE users"},
+		{name: "nested explain", sql: "EXPLAIN EXPLAIN SELECT id FROM users"},
+		{name: "nothing to explain", sql: "EXPLAIN", err: "invalid query type"},
+		{name: "invalid statement", sql: "EXPLAIN SELECT nope FROM users", err: "column NOPE does not exist"},
+	})
+}
+
+func TestExplainExecute(t *testing.T) {
+	runParseTests(t, newPreparedParser, []parseTest{
+		{name: "execute", sql: "EXPLAIN EXECUTE older_than(18)"},
+		{name: "analyze execute", sql: "EXPLAIN ANALYZE EXECUTE rename('ada', 1)"},
+		{name: "unknown statement", sql: "EXPLAIN EXECUTE nope", err: "prepared statement NOPE does not exist"},
+	})
+}
+
+func TestExplainLeavesCatalog(t *testing.T) {
+	parser := newPreparedParser()
+	for _, sql := range []string{
+		"EXPLAIN CREATE VIEW v AS SELECT id FROM users",
+		"EXPLAIN CREATE TABLE notes (id)",
+		"EXPLAIN DEALLOCATE older_than",
+		"EXPLAIN CREATE SCHEMA sales",
+	} {
+		mustParse(t, parser, sql)
+	}
+	if parser.Schema.IsView("V") {
+		t.Fatal("expected the explained view not to be created")
+	}
+	if _, err := parser.Schema.LookupTable(TableName{Name: Identifier{Name: "NOTES"}}); err == nil {
+		t.Fatal("expected the explained table not to be created")
+	}
+	if _, ok := parser.Prepared.Lookup("OLDER_THAN"); !ok {
+		t.Fatal("expected the explained DEALLOCATE to keep the prepared statement")
+	}
+	if _, err := parser.Schema.LookupSchema(Identifier{Name: "SALES"}); err == nil {
+		t.Fatal("expected the explained schema not to be created")
+	}
+}
+
+func TestExplainStatement(t *testing.T) {
+	parsedStmt := mustParse(t, newTestParser(), "EXPLAIN ANALYZE FORMAT JSON UPDATE users SET age = 1 WHERE id = 1")
+	if parsedStmt.QueryType != ExplainQuery {
+		t.Fatalf("expected an %s query, got %s", ExplainQuery, parsedStmt.QueryType)
+	}
+	if want := []string{"USERS"}; !reflect.DeepEqual(parsedStmt.Tables, want) {
+		t.Fatalf("expected the tables of the explained statement %v, got %v", want, parsedStmt.Tables)
+	}
+	if want := []ResultColumn{{Name: "QUERY PLAN", Type: TextType}}; !reflect.DeepEqual(parsedStmt.Result, want) {
+		t.Fatalf("expected the result %v, got %v", want, parsedStmt.Result)
+	}
+	stmt := parsedStmt.Stmt.(*ExplainStmt)
+	if !stmt.Analyze || stmt.Verbose || stmt.Format != "JSON" {
+		t.Fatalf("expected ANALYZE FORMAT JSON, got %+v", stmt)
+	}
+	if _, ok := stmt.Stmt.(*UpdateStmt); !ok {
+		t.Fatalf("expected an explained UPDATE, got %T", stmt.Stmt)
+	}
+}
+
+func TestExplainSafeMode(t *testing.T) {
+	safe := func() *SQLParser {
+		parser := newTestParser()
+		parser.SafeMode = true
+		return parser
+	}
+	runParseTests(t, safe, []parseTest{
+		{name: "explain only", sql: "EXPLAIN DELETE FROM users"},
+		{name: "analyze with where", sql: "EXPLAIN ANALYZE DELETE FROM users WHERE id = 1"},
+		{name: "analyze", sql: "EXPLAIN ANALYZE DELETE FROM users", err: "safe mode: DELETE without a WHERE clause"},
+		{name: "analyze update", sql: "EXPLAIN ANALYZE UPDATE users SET age = 1", err: "safe mode: UPDATE without a WHERE clause"},
+	})
+}
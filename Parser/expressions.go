@@ -0,0 +1,167 @@
+package sqlParser
+
+import "strings"
+
+// Expr is implemented by the expression nodes of the AST. String returns
+// the expression as SQL text.
+type Expr interface {
+	String() string
+	exprNode()
+}
+
+// ColumnRef is a reference to a column, optionally qualified by its table
+// and schema: schema.table.column
+type ColumnRef struct {
+	Trivia
+	Schema Identifier
+	Table  Identifier
+	Column Identifier
+}
+
+func (col *ColumnRef) String() string {
+	var parts []string
+	for _, id := range []Identifier{col.Schema, col.Table, col.Column} {
+		if !id.IsEmpty() {
+			parts = append(parts, id.String())
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// Star is the * or table.* of a select list, standing for every column of
+// the tables of the statement or of the one table.
+type Star struct {
+	Trivia
+	Table TableName // empty for a plain *
+}
+
+func (star *Star) String() string {
+	if star.Table.Name.IsEmpty() {
+		return "*"
+	}
+	return star.Table.String() + ".*"
+}
+
+// Param is a bind parameter: ?, $1, :name or @name.
+type Param struct {
+	Trivia
+	Raw   string // the parameter as written in the statement
+	Index int    // position of a ? parameter or number of a $n one, 0 for named ones
+	Name  string // name of a :name or @name parameter
+}
+
+func (param *Param) String() string {
+	return param.Raw
+}
+
+// Default is the DEFAULT keyword used in place of a value.
+type Default struct {
+	Trivia
+}
+
+func (*Default) String() string {
+	return "DEFAULT"
+}
+
+// BinaryExpr is an arithmetic or concatenation operation: left op right
+type BinaryExpr struct {
+	Trivia
+	Left     Expr
+	Operator string // +, -, *, /, % or ||
+	Right    Expr
+}
+
+func (expr *BinaryExpr) String() string {
+	return expr.Left.String() + " " + expr.Operator + " " + expr.Right.String()
+}
+
+// UnaryExpr is a sign applied to an expression: -value
+type UnaryExpr struct {
+	Trivia
+	Operator string // + or -
+	Operand  Expr
+}
+
+func (expr *UnaryExpr) String() string {
+	return expr.Operator + expr.Operand.String()
+}
+
+// ParenExpr is an expression written between parentheses.
+type ParenExpr struct {
+	Trivia
+	Expr Expr
+}
+
+func (expr *ParenExpr) String() string {
+	return "(" + expr.Expr.String() + ")"
+}
+
+func (*BinaryExpr) exprNode() {}
+func (*ColumnRef) exprNode()  {}
+func (*ParenExpr) exprNode()  {}
+func (*UnaryExpr) exprNode()  {}
+func (*Default) exprNode()    {}
+func (*Param) exprNode()      {}
+func (*Star) exprNode()       {}
+func (*Literal) exprNode()    {}
+
+// walkExpr calls visit on the expression and on the expressions nested in
+// it, the PARTITION BY and ORDER BY of a window included. The expressions
+// nested in one are skipped when visit returns false for it.
+func walkExpr(expr Expr, visit func(Expr) bool) {
+	if expr == nil || !visit(expr) {
+		return
+	}
+	var nested []Expr
+	switch expr := expr.(type) {
+	case *BinaryExpr:
+		nested = []Expr{expr.Left, expr.Right}
+	case *UnaryExpr:
+		nested = []Expr{expr.Operand}
+	case *ParenExpr:
+		nested = []Expr{expr.Expr}
+	case *CastExpr:
+		nested = []Expr{expr.Expr}
+	case *FuncCall:
+		nested = append(nested, expr.Args...)
+		if expr.Over != nil {
+			nested = append(nested, expr.Over.PartitionBy...)
+			for _, item := range expr.Over.OrderBy {
+				nested = append(nested, item.Expr)
+			}
+		}
+	case *CaseExpr:
+		nested = []Expr{expr.Operand, expr.Else}
+		for _, when := range expr.Whens {
+			nested = append(nested, when.When, when.Then)
+		}
+	case *Comparison:
+		nested = []Expr{expr.Left, expr.Right}
+	case *LogicalExpr:
+		nested = []Expr{expr.Left, expr.Right}
+	case *NotExpr:
+		nested = []Expr{expr.Expr}
+	case *InExpr:
+		nested = append([]Expr{expr.Expr}, expr.List...)
+	case *BetweenExpr:
+		nested = []Expr{expr.Expr, expr.Low, expr.High}
+	case *LikeExpr:
+		nested = []Expr{expr.Expr, expr.Pattern, expr.Escape}
+	case *IsNullExpr:
+		nested = []Expr{expr.Expr}
+	case *IsDistinctExpr:
+		nested = []Expr{expr.Left, expr.Right}
+	}
+	for _, child := range nested {
+		walkExpr(child, visit)
+	}
+}
+
+// exprStrings returns the SQL text of each expression.
+func exprStrings(exprs []Expr) []string {
+	strs := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		strs = append(strs, expr.String())
+	}
+	return strs
+}
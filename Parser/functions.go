@@ -0,0 +1,345 @@
+package sqlParser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FuncCall is a function call: name(arg1, arg2, ...)
+type FuncCall struct {
+	Trivia
+	Name     Identifier
+	Args     []Expr
+	Star     bool        // COUNT(*)
+	Distinct bool        // COUNT(DISTINCT expr)
+	Keywords bool        // the arguments were written with keywords: SUBSTRING(s FROM 1 FOR 3)
+	Over     *WindowSpec // the window of a window function, nil without OVER
+}
+
+func (call *FuncCall) String() string {
+	var args string
+	switch {
+	case call.Star:
+		args = "*"
+	case call.Keywords:
+		args = call.Args[0].String() + " FROM " + call.Args[1].String()
+		if len(call.Args) > 2 {
+			args += " FOR " + call.Args[2].String()
+		}
+	default:
+		args = strings.Join(exprStrings(call.Args), ", ")
+	}
+	if call.Distinct {
+		args = "DISTINCT " + args
+	}
+	if call.Over != nil {
+		return call.Name.String() + "(" + args + ") OVER " + call.Over.String()
+	}
+	return call.Name.String() + "(" + args + ")"
+}
+
+// CastExpr converts a value to a type: CAST(expr AS type)
+type CastExpr struct {
+	Trivia
+	Expr     Expr
+	TypeName string // the type as written: VARCHAR(20)
+}
+
+func (cast *CastExpr) String() string {
+	return "CAST(" + cast.Expr.String() + " AS " + cast.TypeName + ")"
+}
+
+func (*CastExpr) exprNode() {}
+func (*FuncCall) exprNode() {}
+
+// Function describes a function that can be called in expressions.
+type Function struct {
+	Name      string
+	MinArgs   int
+	MaxArgs   int        // -1 for a variable number of arguments
+	Args      []DataType // the argument types, the last one repeats; UnknownType accepts any type
+	Returns   DataType
+	Aggregate bool // the function computes a value over a group of rows, as COUNT
+	Window    bool // the function is computed over the window of an OVER clause, as ROW_NUMBER
+	// ReturnType, when set, computes the return type from the argument
+	// types, rejecting the types the function does not accept.
+	ReturnType func(args []DataType) (DataType, error)
+}
+
+// argType returns the type expected for the argument i, UnknownType for any.
+func (fn Function) argType(i int) DataType {
+	switch {
+	case len(fn.Args) == 0:
+		return UnknownType
+	case i < len(fn.Args):
+		return fn.Args[i]
+	}
+	return fn.Args[len(fn.Args)-1]
+}
+
+// FunctionRegistry is the catalog of the functions the semantic analysis
+// checks the function calls against. The names are stored in upper case.
+type FunctionRegistry struct {
+	functions map[string]Function
+}
+
+// NewFunctionRegistry returns a registry holding the built-in functions.
+func NewFunctionRegistry() *FunctionRegistry {
+	registry := &FunctionRegistry{functions: map[string]Function{}}
+	for _, fn := range builtinFunctions {
+		registry.Register(fn)
+	}
+	return registry
+}
+
+// Register adds a function to the registry, replacing the function of the
+// same name. It lets engine specific and user defined functions be called.
+func (registry *FunctionRegistry) Register(fn Function) error {
+	if fn.Name == "" {
+		return errors.New("function name is empty")
+	}
+	if fn.MaxArgs >= 0 && fn.MaxArgs < fn.MinArgs {
+		return fmt.Errorf("function %s takes at most %d arguments but at least %d", fn.Name, fn.MaxArgs, fn.MinArgs)
+	}
+	registry.functions[strings.ToUpper(fn.Name)] = fn
+	return nil
+}
+
+// Lookup returns the function of the given name, ignoring case.
+func (registry *FunctionRegistry) Lookup(name string) (Function, bool) {
+	fn, ok := registry.functions[strings.ToUpper(name)]
+	return fn, ok
+}
+
+// defaultFunctions is used by the parsers built without NewSQLParser.
+var defaultFunctions = NewFunctionRegistry()
+
+// functions returns the function registry of the parser.
+func (parser *SQLParser) functions() *FunctionRegistry {
+	if parser.Functions == nil {
+		return defaultFunctions
+	}
+	return parser.Functions
+}
+
+var builtinFunctions = []Function{
+	// text functions
+	{Name: "UPPER", MinArgs: 1, MaxArgs: 1, Args: []DataType{TextType}, Returns: TextType},
+	{Name: "LOWER", MinArgs: 1, MaxArgs: 1, Args: []DataType{TextType}, Returns: TextType},
+	{Name: "TRIM", MinArgs: 1, MaxArgs: 2, Args: []DataType{TextType}, Returns: TextType},
+	{Name: "LTRIM", MinArgs: 1, MaxArgs: 2, Args: []DataType{TextType}, Returns: TextType},
+	{Name: "RTRIM", MinArgs: 1, MaxArgs: 2, Args: []DataType{TextType}, Returns: TextType},
+	{Name: "LENGTH", MinArgs: 1, MaxArgs: 1, Args: []DataType{TextType}, Returns: IntegerType},
+	{Name: "CHAR_LENGTH", MinArgs: 1, MaxArgs: 1, Args: []DataType{TextType}, Returns: IntegerType},
+	{Name: "SUBSTRING", MinArgs: 2, MaxArgs: 3, Args: []DataType{TextType, IntegerType}, Returns: TextType},
+	{Name: "SUBSTR", MinArgs: 2, MaxArgs: 3, Args: []DataType{TextType, IntegerType}, Returns: TextType},
+	{Name: "REPLACE", MinArgs: 3, MaxArgs: 3, Args: []DataType{TextType}, Returns: TextType},
+	{Name: "CONCAT", MinArgs: 1, MaxArgs: -1, Returns: TextType},
+
+	// null handling
+	{Name: "COALESCE", MinArgs: 1, MaxArgs: -1, ReturnType: commonArgType},
+	{Name: "NULLIF", MinArgs: 2, MaxArgs: 2, ReturnType: firstComparableArgType},
+
+	// numeric functions
+	{Name: "ABS", MinArgs: 1, MaxArgs: 1, ReturnType: numericArgType},
+	{Name: "CEIL", MinArgs: 1, MaxArgs: 1, ReturnType: numericArgType},
+	{Name: "FLOOR", MinArgs: 1, MaxArgs: 1, ReturnType: numericArgType},
+	{Name: "ROUND", MinArgs: 1, MaxArgs: 2, ReturnType: numericArgType},
+
+	// date and time functions
+	{Name: "NOW", MinArgs: 0, MaxArgs: 0, Returns: TimestampType},
+
+	// aggregate functions
+	{Name: "COUNT", MinArgs: 1, MaxArgs: 1, Returns: IntegerType, Aggregate: true},
+	{Name: "SUM", MinArgs: 1, MaxArgs: 1, ReturnType: numericArgType, Aggregate: true},
+	{Name: "AVG", MinArgs: 1, MaxArgs: 1, ReturnType: averageType, Aggregate: true},
+	{Name: "MIN", MinArgs: 1, MaxArgs: 1, ReturnType: firstArgType, Aggregate: true},
+	{Name: "MAX", MinArgs: 1, MaxArgs: 1, ReturnType: firstArgType, Aggregate: true},
+
+	// window functions
+	{Name: "ROW_NUMBER", MinArgs: 0, MaxArgs: 0, Returns: IntegerType, Window: true},
+	{Name: "RANK", MinArgs: 0, MaxArgs: 0, Returns: IntegerType, Window: true},
+	{Name: "DENSE_RANK", MinArgs: 0, MaxArgs: 0, Returns: IntegerType, Window: true},
+	{Name: "PERCENT_RANK", MinArgs: 0, MaxArgs: 0, Returns: FloatType, Window: true},
+	{Name: "CUME_DIST", MinArgs: 0, MaxArgs: 0, Returns: FloatType, Window: true},
+	{Name: "NTILE", MinArgs: 1, MaxArgs: 1, Args: []DataType{IntegerType}, Returns: IntegerType, Window: true},
+	{Name: "LAG", MinArgs: 1, MaxArgs: 3, Args: []DataType{UnknownType, IntegerType, UnknownType}, ReturnType: lagType, Window: true},
+	{Name: "LEAD", MinArgs: 1, MaxArgs: 3, Args: []DataType{UnknownType, IntegerType, UnknownType}, ReturnType: lagType, Window: true},
+	{Name: "FIRST_VALUE", MinArgs: 1, MaxArgs: 1, ReturnType: firstArgType, Window: true},
+	{Name: "LAST_VALUE", MinArgs: 1, MaxArgs: 1, ReturnType: firstArgType, Window: true},
+	{Name: "NTH_VALUE", MinArgs: 2, MaxArgs: 2, Args: []DataType{UnknownType, IntegerType}, ReturnType: firstArgType, Window: true},
+}
+
+// commonArgType returns the type all the arguments convert to.
+func commonArgType(args []DataType) (DataType, error) {
+	common := UnknownType
+	for _, arg := range args {
+		typ, ok := commonType(common, arg)
+		if !ok {
+			return UnknownType, fmt.Errorf("arguments of types %s and %s can not be mixed", common, arg)
+		}
+		common = typ
+	}
+	return common, nil
+}
+
+// firstComparableArgType returns the type of the first argument, which must
+// be comparable with the second one.
+func firstComparableArgType(args []DataType) (DataType, error) {
+	if _, err := commonArgType(args); err != nil {
+		return UnknownType, err
+	}
+	return args[0], nil
+}
+
+// firstArgType returns the type of the first argument.
+func firstArgType(args []DataType) (DataType, error) {
+	return args[0], nil
+}
+
+// numericArgType returns the type of the first argument, which must be a
+// number, the next arguments must be integers.
+func numericArgType(args []DataType) (DataType, error) {
+	if !args[0].IsNumeric() && args[0] != UnknownType && args[0] != NullType {
+		return UnknownType, fmt.Errorf("argument of type %s is not a number", args[0])
+	}
+	for _, arg := range args[1:] {
+		if arg != IntegerType && arg != UnknownType && arg != NullType {
+			return UnknownType, fmt.Errorf("argument of type %s is not an integer", arg)
+		}
+	}
+	return args[0], nil
+}
+
+// averageType returns the type of the average of numbers, a decimal for
+// integers.
+func averageType(args []DataType) (DataType, error) {
+	typ, err := numericArgType(args)
+	if typ == IntegerType {
+		return DecimalType, err
+	}
+	return typ, err
+}
+
+// funcCallType checks a function call against the registry of the parser and
+// returns the type of its value.
+func (parser *SQLParser) funcCallType(call *FuncCall, scope exprScope) (DataType, error) {
+	fn, ok := parser.functions().Lookup(call.Name.Name)
+	if !ok {
+		return UnknownType, fmt.Errorf("function %s does not exist", call.Name)
+	}
+	switch {
+	case call.Over != nil && !fn.Window && !fn.Aggregate:
+		return UnknownType, fmt.Errorf("%s is not a window or aggregate function", call.Name)
+	case call.Over != nil && !scope.windows:
+		return UnknownType, fmt.Errorf("window function %s is not allowed here", call.Name)
+	case call.Over == nil && fn.Window:
+		return UnknownType, fmt.Errorf("window function %s requires an OVER clause", call.Name)
+	case call.Over == nil && fn.Aggregate && !scope.aggregates:
+		return UnknownType, fmt.Errorf("aggregate function %s is not allowed here", call.Name)
+	}
+	if call.Over != nil {
+		if err := parser.validateWindow(call.Over, scope); err != nil {
+			return UnknownType, err
+		}
+	}
+	if !fn.Aggregate && (call.Star || call.Distinct) {
+		return UnknownType, fmt.Errorf("%s is not an aggregate function", call.Name)
+	}
+
+	count := len(call.Args)
+	if call.Star {
+		count = 1
+	}
+	if count < fn.MinArgs || (fn.MaxArgs >= 0 && count > fn.MaxArgs) {
+		return UnknownType, fmt.Errorf("function %s does not take %d arguments", call.Name, count)
+	}
+
+	// the aggregated values are not aggregates themselves, the arguments of
+	// a window function are computed before it
+	argScope := scope
+	argScope.aggregates = scope.aggregates && !(fn.Aggregate && call.Over == nil)
+	argScope.windows = false
+	types := make([]DataType, 0, len(call.Args))
+	for i, arg := range call.Args {
+		expected := fn.argType(i)
+		if param, ok := arg.(*Param); ok {
+			if err := scope.params.add(param, "", "", expected); err != nil {
+				return UnknownType, err
+			}
+			types = append(types, expected)
+			continue
+		}
+		typ, err := parser.exprType(arg, argScope)
+		if err != nil {
+			return UnknownType, err
+		}
+		if expected != UnknownType && !acceptsArg(arg, typ, expected) {
+			return UnknownType, fmt.Errorf("function %s expects %s for argument %d, not %s of type %s", call.Name, expected, i+1, arg, typ)
+		}
+		types = append(types, typ)
+	}
+
+	if fn.ReturnType == nil {
+		return fn.Returns, nil
+	}
+	if call.Star {
+		types = []DataType{UnknownType}
+	}
+	typ, err := fn.ReturnType(types)
+	if err != nil {
+		return UnknownType, fmt.Errorf("function %s: %v", call.Name, err)
+	}
+	return typ, nil
+}
+
+// acceptsArg reports whether the argument can be passed for a parameter of
+// the expected type, string constants being accepted for the types they hold
+// a valid value of.
+func acceptsArg(arg Expr, typ, expected DataType) bool {
+	if _, ok := commonType(typ, expected); ok {
+		return true
+	}
+	lit, ok := arg.(*Literal)
+	return ok && lit.Kind == StringLiteral && convertible(lit.Value.(string), expected)
+}
+
+// castType checks a CAST and returns the type the value is converted to.
+func (parser *SQLParser) castType(cast *CastExpr, scope exprScope) (DataType, error) {
+	target := ParseDataType(cast.TypeName)
+	if target == UnknownType {
+		return UnknownType, fmt.Errorf("type %s does not exist", cast.TypeName)
+	}
+	if param, ok := cast.Expr.(*Param); ok {
+		return target, scope.params.add(param, "", "", UnknownType)
+	}
+	typ, err := parser.exprType(cast.Expr, scope)
+	if err != nil {
+		return UnknownType, err
+	}
+	if lit, ok := cast.Expr.(*Literal); ok && lit.Kind == StringLiteral && !convertible(lit.Value.(string), target) {
+		return UnknownType, fmt.Errorf("can not cast %s to %s", lit.Raw, cast.TypeName)
+	}
+	if !castable(typ, target) {
+		return UnknownType, fmt.Errorf("can not cast %s of type %s to %s", cast.Expr, typ, cast.TypeName)
+	}
+	return target, nil
+}
+
+// castable reports whether a value of type from can be converted to type to.
+// Texts convert to and from any type, numbers to booleans and dates to times.
+func castable(from, to DataType) bool {
+	switch {
+	case from == TextType || to == TextType:
+		return true
+	case from.IsNumeric() && to.IsNumeric():
+		return true
+	case (from == IntegerType && to == BooleanType) || (from == BooleanType && to == IntegerType):
+		return true
+	case (from == DateType || from == TimestampType) && (to == DateType || to == TimestampType || to == TimeType):
+		return true
+	}
+	_, ok := commonType(from, to)
+	return ok
+}
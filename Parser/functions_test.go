@@ -0,0 +1,56 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFunctionCalls(t *testing.T) {
+	runParseTests(t, newTestParser, []parseTest{
+		{name: "text function", sql: "SELECT UPPER(name) FROM users WHERE LENGTH(email) > 3"},
+		{name: "lower case name", sql: "SELECT lower(name) FROM users"},
+		{name: "variable arguments", sql: "SELECT CONCAT(name, ' ', email, age) FROM users"},
+		{name: "substring from for", sql: "SELECT SUBSTRING(name FROM 1 FOR 3) FROM users"},
+		{name: "nested calls", sql: "SELECT COALESCE(UPPER(name), 'none') FROM users"},
+		{name: "cast", sql: "SELECT CAST(age AS varchar(10)) FROM users WHERE CAST('42' AS int) = age"},
+		{name: "unknown function", sql: "SELECT nope(name) FROM users", err: "function NOPE does not exist"},
+		{name: "argument count", sql: "SELECT UPPER(name, email) FROM users", err: "function UPPER does not take 2 arguments"},
+		{name: "count of a column", sql: "SELECT COUNT(email), COUNT(DISTINCT name) FROM users"},
+		{name: "count without argument", sql: "SELECT COUNT() FROM users", err: "function COUNT does not take 0 arguments"},
+		{name: "argument type", sql: "SELECT UPPER(age) FROM users", err: "function UPPER expects TEXT for argument 1, not AGE of type INTEGER"},
+		{name: "numeric argument", sql: "SELECT ABS(name) FROM users", err: "function ABS: argument of type TEXT is not a number"},
+		{name: "mixed arguments", sql: "SELECT COALESCE(age, name) FROM users", err: "function COALESCE: arguments of types INTEGER and TEXT can not be mixed"},
+		{name: "unknown cast type", sql: "SELECT CAST(age AS nope) FROM users", err: "type NOPE does not exist"},
+		{name: "invalid cast", sql: "SELECT CAST('abc' AS int) FROM users", err: "can not cast 'abc' to INT"},
+		{name: "cast of a column", sql: "SELECT CAST(created_at AS int) FROM orders", err: "can not cast CREATED_AT of type TIMESTAMP to INT"},
+	})
+}
+
+func TestFunctionTypes(t *testing.T) {
+	parsedStmt := mustParse(t, newTestParser(), "SELECT UPPER(CAST(id AS text)), LENGTH(CAST(id AS text)), ROUND(total), COALESCE(total, 1), NOW(), CAST(id AS text) FROM orders")
+	var types []DataType
+	for _, column := range parsedStmt.Result {
+		types = append(types, column.Type)
+	}
+	want := []DataType{TextType, IntegerType, DecimalType, DecimalType, TimestampType, TextType}
+	if !reflect.DeepEqual(types, want) {
+		t.Fatalf("expected the result types %v, got %v", want, types)
+	}
+}
+
+func TestRegisterFunction(t *testing.T) {
+	parser := newTestParser()
+	if err := parser.Functions.Register(Function{Name: "slugify", MinArgs: 1, MaxArgs: 1, Args: []DataType{TextType}, Returns: TextType}); err != nil {
+		t.Fatal(err)
+	}
+	parsedStmt := mustParse(t, parser, "SELECT SLUGIFY(name) AS slug FROM users")
+	if parsedStmt.Result[0].Type != TextType {
+		t.Fatalf("expected a TEXT result, got %s", parsedStmt.Result[0].Type)
+	}
+	if _, err := newTestParser().ParseSQL("SELECT SLUGIFY(name) FROM users"); err == nil {
+		t.Fatal("expected the function to be registered on its parser only")
+	}
+
+	checkError(t, parser.Functions.Register(Function{}), "function name is empty")
+	checkError(t, parser.Functions.Register(Function{Name: "F", MinArgs: 2, MaxArgs: 1}), "function F takes at most 1 arguments but at least 2")
+}
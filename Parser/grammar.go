@@ -0,0 +1,2234 @@
+package sqlParser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// stmtParser walks over the tokens of a statement and builds its AST by
+// recursive descent, one method per grammar rule.
+type stmtParser struct {
+	tokens  []Token
+	pos     int
+	dialect Dialect
+	pending []Comment // comments of the consumed tokens not attached to a node yet
+	trivia  Trivia    // comments of the statement that belong to no smaller node
+
+	positional int  // number of ? parameters met so far
+	numbered   bool // a $n parameter was met
+}
+
+func newStmtParser(tokens []Token, dialect Dialect) *stmtParser {
+	return &stmtParser{tokens: tokens, dialect: dialect}
+}
+
+// parseStatement parses a whole statement, an optional semicolon may end it.
+func (p *stmtParser) parseStatement() (Statement, error) {
+	stmt, err := p.parseStatementBody()
+	if err != nil {
+		return nil, err
+	}
+
+	p.acceptSymbol(";")
+	if tok := p.peek(); tok.Type != EOFToken {
+		return nil, fmt.Errorf("syntax error: unexpected %q at position %d after the end of the statement", tok.Value, tok.Pos)
+	}
+	p.next()
+
+	p.trivia.attach(p.takePending())
+	stmt.attach(p.trivia.Comments)
+	return stmt, nil
+}
+
+// parseStatementBody parses a statement from its first word up to its end.
+func (p *stmtParser) parseStatementBody() (Statement, error) {
+	first := p.next()
+	if first.Type != KeywordToken && first.Type != IdentifierToken {
+		return nil, errors.New("invalid query type")
+	}
+	queryType, err := paresQueryType(first.Value)
+	if err != nil {
+		return nil, err
+	}
+	// comments before the first keyword belong to the statement itself
+	p.trivia.attach(p.takePending())
+
+	var stmt Statement
+	switch queryType {
+	case SelectQuery:
+		stmt, err = p.parseSelect()
+	case InsertQuery:
+		stmt, err = p.parseInsert()
+	case UpdateQuery:
+		stmt, err = p.parseUpdate()
+	case DeleteQuery:
+		stmt, err = p.parseDelete()
+	case DropQuery:
+		stmt, err = p.parseDrop()
+	case CreateQuery:
+		stmt, err = p.parseCreate()
+	case TransactionQuery:
+		stmt, err = p.parseTransaction(first.Value)
+	case TruncateQuery:
+		stmt, err = p.parseTruncate()
+	case ShowQuery:
+		stmt, err = p.parseShow(first.Value)
+	case ExplainQuery:
+		stmt, err = p.parseExplain()
+	case PrepareQuery:
+		stmt, err = p.parsePrepare()
+	case ExecuteQuery:
+		stmt, err = p.parseExecute()
+	case DeallocateQuery:
+		stmt, err = p.parseDeallocate()
+	case GrantQuery, RevokeQuery:
+		stmt, err = p.parseGrant(queryType == RevokeQuery)
+	case UseQuery:
+		stmt, err = p.parseUse()
+	case SetQuery:
+		stmt, err = p.parseSet()
+	default:
+		return nil, errors.New("unsupported query type yet :(")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// explainFormats are the output formats of EXPLAIN.
+var explainFormats = map[string]bool{"TEXT": true, "JSON": true, "XML": true, "YAML": true}
+
+// EXPLAIN [ANALYZE] [VERBOSE] [FORMAT TEXT | JSON | XML | YAML] statement;
+func (p *stmtParser) parseExplain() (*ExplainStmt, error) {
+	stmt := &ExplainStmt{}
+	stmt.Analyze = p.acceptWord("ANALYZE")
+	stmt.Verbose = p.acceptWord("VERBOSE")
+	if p.acceptWord("FORMAT") {
+		tok := p.peek()
+		if tok.Type != IdentifierToken || !explainFormats[tok.Value] {
+			return nil, p.unexpected("TEXT, JSON, XML or YAML")
+		}
+		p.next()
+		stmt.Format = tok.Value
+	}
+	inner, err := p.parseStatementBody()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Stmt = inner
+	return stmt, nil
+}
+
+// SELECT [ALL | DISTINCT | DISTINCT ON (expr, ...)] expr [[AS] alias], ...
+// FROM table_name [WHERE condition] [GROUP BY expr, ...] [WINDOW name AS (...), ...]
+// [ORDER BY expr [ASC | DESC], ...] [LIMIT count] [OFFSET count];
+func (p *stmtParser) parseSelect() (*SelectStmt, error) {
+	stmt := &SelectStmt{}
+
+	switch {
+	case p.acceptWord("ALL"):
+		stmt.All = true
+	case p.acceptWord("DISTINCT"):
+		stmt.Distinct = true
+		if p.acceptKeyword("ON") {
+			if !p.dialect.DistinctOn {
+				return nil, fmt.Errorf("syntax error: %s does not support DISTINCT ON", p.dialect.Name)
+			}
+			if err := p.expectSymbol("("); err != nil {
+				return nil, err
+			}
+			for {
+				expr, err := p.parseExpr()
+				if err != nil {
+					return nil, err
+				}
+				stmt.DistinctOn = append(stmt.DistinctOn, expr)
+				if !p.acceptSymbol(",") {
+					break
+				}
+			}
+			if err := p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if tok := p.peek(); tok.Type == KeywordToken && tok.Value == "FROM" {
+		return nil, errors.New("missing columns in SELECT statement")
+	}
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Columns = append(stmt.Columns, item)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	if err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	table, err := p.parseTableName("SELECT")
+	if err != nil {
+		return nil, err
+	}
+	stmt.Tables = []TableName{table}
+
+	stmt.Where, err = p.parseWhere()
+	if err != nil {
+		return nil, err
+	}
+	if p.acceptWord("GROUP") {
+		if err := p.expectWord("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			stmt.GroupBy = append(stmt.GroupBy, expr)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	if p.acceptWord("WINDOW") {
+		stmt.Windows, err = p.parseWindowClause()
+		if err != nil {
+			return nil, err
+		}
+	}
+	stmt.OrderBy, err = p.parseOrderBy()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Limit, err = p.parseLimit()
+	if err != nil {
+		return nil, err
+	}
+	if p.acceptWord("OFFSET") {
+		stmt.Offset, err = p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stmt, nil
+}
+
+// parseWindowClause parses the windows defined by the WINDOW clause.
+// WINDOW name AS (...), ...
+func (p *stmtParser) parseWindowClause() ([]NamedWindow, error) {
+	var windows []NamedWindow
+	for {
+		name, ok := p.acceptIdentifier()
+		if !ok {
+			return nil, p.unexpected("a window name")
+		}
+		if err := p.expectKeyword("AS"); err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		spec, err := p.parseWindowSpec()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		window := NamedWindow{Name: name, Spec: spec}
+		p.attachComments(&window.Trivia)
+		windows = append(windows, window)
+		if !p.acceptSymbol(",") {
+			return windows, nil
+		}
+	}
+}
+
+// INSERT INTO table_name [(col1, col2, ...)] VALUES (value1, value2, ...), (...), ...;
+// INSERT INTO table_name [(col1, col2, ...)] SELECT ...;
+// INSERT INTO table_name DEFAULT VALUES;
+// INSERT INTO table_name ... VALUES (...) [AS alias] ON DUPLICATE KEY UPDATE ...;
+func (p *stmtParser) parseInsert() (*InsertStmt, error) {
+	stmt := &InsertStmt{}
+
+	if err := p.expectKeyword("INTO"); err != nil {
+		return nil, err
+	}
+	table, err := p.parseTableName("INSERT")
+	if err != nil {
+		return nil, err
+	}
+	stmt.Table = table
+
+	// the column list is optional, the schema column order is used without it
+	if p.acceptSymbol("(") {
+		stmt.Columns, err = p.parseIdentifierList("column", "INSERT")
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptSymbol(")") {
+			return nil, errors.New("missing parenthesis in INSERT statement")
+		}
+	}
+
+	switch {
+	case p.acceptKeyword("VALUES"):
+		for {
+			row, err := p.parseInsertRow()
+			if err != nil {
+				return nil, err
+			}
+			stmt.Rows = append(stmt.Rows, row)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		// the row alias of MySQL upserts
+		if p.dialect.OnDuplicateKey && p.acceptKeyword("AS") {
+			alias, ok := p.acceptIdentifier()
+			if !ok {
+				return nil, p.unexpected("a row alias")
+			}
+			stmt.RowAlias = alias
+		}
+
+	case p.acceptKeyword("SELECT"):
+		stmt.Select, err = p.parseSelect()
+		if err != nil {
+			return nil, err
+		}
+
+	case p.acceptKeyword("DEFAULT"):
+		if err := p.expectKeyword("VALUES"); err != nil {
+			return nil, err
+		}
+		if len(stmt.Columns) > 0 {
+			return nil, errors.New("syntax error: DEFAULT VALUES can not be used with a column list")
+		}
+		stmt.DefaultValues = true
+
+	default:
+		return nil, p.unexpected("VALUES, SELECT or DEFAULT VALUES")
+	}
+
+	if p.acceptKeyword("ON") {
+		stmt.OnConflict, err = p.parseOnConflict()
+		if err != nil {
+			return nil, err
+		}
+	}
+	stmt.Returning, err = p.parseReturning()
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseOnConflict parses the upsert clause following ON.
+// ON CONFLICT [(col1, col2) | ON CONSTRAINT name] DO NOTHING
+// ON CONFLICT [(col1, col2) | ON CONSTRAINT name] DO UPDATE SET col1 = value1, ... WHERE condition
+// ON DUPLICATE KEY UPDATE col1 = value1, ...
+func (p *stmtParser) parseOnConflict() (*OnConflict, error) {
+	clause := &OnConflict{}
+	var err error
+
+	if p.acceptWord("DUPLICATE") {
+		if !p.dialect.OnDuplicateKey {
+			return nil, fmt.Errorf("syntax error: %s does not support ON DUPLICATE KEY UPDATE", p.dialect.Name)
+		}
+		if err := p.expectWord("KEY"); err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("UPDATE"); err != nil {
+			return nil, err
+		}
+		clause.DuplicateKey = true
+		clause.Set, err = p.parseAssignments("INSERT")
+		if err != nil {
+			return nil, err
+		}
+		p.attachComments(&clause.Trivia)
+		return clause, nil
+	}
+
+	if err := p.expectWord("CONFLICT"); err != nil {
+		return nil, err
+	}
+	if !p.dialect.OnConflict {
+		return nil, fmt.Errorf("syntax error: %s does not support ON CONFLICT", p.dialect.Name)
+	}
+	switch {
+	case p.acceptSymbol("("):
+		clause.Target, err = p.parseIdentifierList("column", "INSERT")
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+	case p.acceptKeyword("ON"):
+		if err := p.expectWord("CONSTRAINT"); err != nil {
+			return nil, err
+		}
+		name, ok := p.acceptIdentifier()
+		if !ok {
+			return nil, p.unexpected("a constraint name")
+		}
+		clause.Constraint = name
+	}
+
+	if err := p.expectWord("DO"); err != nil {
+		return nil, err
+	}
+	if p.acceptWord("NOTHING") {
+		clause.DoNothing = true
+		p.attachComments(&clause.Trivia)
+		return clause, nil
+	}
+	if !p.acceptKeyword("UPDATE") {
+		return nil, p.unexpected("NOTHING or UPDATE")
+	}
+	if err := p.expectKeyword("SET"); err != nil {
+		return nil, err
+	}
+	clause.Set, err = p.parseAssignments("INSERT")
+	if err != nil {
+		return nil, err
+	}
+	clause.Where, err = p.parseWhere()
+	if err != nil {
+		return nil, err
+	}
+	p.attachComments(&clause.Trivia)
+	return clause, nil
+}
+
+// parseInsertRow parses a parenthesized row of values, DEFAULT standing for
+// the default value of the column.
+// (value1, DEFAULT, ...)
+func (p *stmtParser) parseInsertRow() ([]Expr, error) {
+	if !p.acceptSymbol("(") {
+		return nil, errors.New("missing parenthesis in INSERT statement")
+	}
+	var row []Expr
+	for {
+		value, err := p.parseAssignedValue()
+		if err != nil {
+			return nil, err
+		}
+		row = append(row, value)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	if !p.acceptSymbol(")") {
+		return nil, errors.New("missing parenthesis in INSERT statement")
+	}
+	return row, nil
+}
+
+// UPDATE table_name SET col1 = value1, (col2, col3) = (value2, value3), ...
+// [FROM table2, ...] WHERE condition [ORDER BY ...] [LIMIT n] [RETURNING ...];
+// UPDATE table1, table2 SET table1.col1 = table2.col2, ... WHERE condition;
+func (p *stmtParser) parseUpdate() (*UpdateStmt, error) {
+	stmt := &UpdateStmt{}
+
+	table, err := p.parseTableName("UPDATE")
+	if err != nil {
+		return nil, err
+	}
+	stmt.Table = table
+	for p.acceptSymbol(",") {
+		if !p.dialect.MultiTableUpdate {
+			return nil, fmt.Errorf("syntax error: %s does not support multiple-table UPDATE statements", p.dialect.Name)
+		}
+		table, err := p.parseTableName("UPDATE")
+		if err != nil {
+			return nil, err
+		}
+		stmt.Joined = append(stmt.Joined, table)
+	}
+
+	if err := p.expectKeyword("SET"); err != nil {
+		return nil, err
+	}
+	stmt.Set, err = p.parseAssignments("UPDATE")
+	if err != nil {
+		return nil, err
+	}
+
+	if p.acceptKeyword("FROM") {
+		if !p.dialect.UpdateFrom {
+			return nil, fmt.Errorf("syntax error: %s does not support FROM in UPDATE statements", p.dialect.Name)
+		}
+		for {
+			table, err := p.parseTableName("UPDATE")
+			if err != nil {
+				return nil, err
+			}
+			stmt.From = append(stmt.From, table)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+
+	stmt.Where, err = p.parseWhere()
+	if err != nil {
+		return nil, err
+	}
+	stmt.OrderBy, stmt.Limit, err = p.parseWriteOrderLimit("UPDATE")
+	if err != nil {
+		return nil, err
+	}
+	if len(stmt.Joined) > 0 && (stmt.OrderBy != nil || stmt.Limit != nil) {
+		return nil, errors.New("syntax error: ORDER BY and LIMIT can not be used in multiple-table UPDATE statements")
+	}
+	stmt.Returning, err = p.parseReturning()
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseWriteOrderLimit parses the ORDER BY and LIMIT clauses of UPDATE and
+// DELETE statements, for the dialects allowing them.
+func (p *stmtParser) parseWriteOrderLimit(stmtType string) ([]OrderItem, Expr, error) {
+	tok := p.peek()
+	if !p.dialect.WriteOrderLimit {
+		switch {
+		case tok.Type == IdentifierToken && tok.Value == "ORDER":
+			return nil, nil, fmt.Errorf("syntax error: %s does not support ORDER BY in %s statements", p.dialect.Name, stmtType)
+		case tok.Type == IdentifierToken && tok.Value == "LIMIT":
+			return nil, nil, fmt.Errorf("syntax error: %s does not support LIMIT in %s statements", p.dialect.Name, stmtType)
+		}
+		return nil, nil, nil
+	}
+	orderBy, err := p.parseOrderBy()
+	if err != nil {
+		return nil, nil, err
+	}
+	limit, err := p.parseLimit()
+	if err != nil {
+		return nil, nil, err
+	}
+	return orderBy, limit, nil
+}
+
+// ORDER BY expr [ASC | DESC], ...
+func (p *stmtParser) parseOrderBy() ([]OrderItem, error) {
+	if !p.acceptWord("ORDER") {
+		return nil, nil
+	}
+	if err := p.expectWord("BY"); err != nil {
+		return nil, err
+	}
+	var items []OrderItem
+	for {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		item := OrderItem{Expr: expr}
+		if p.acceptWord("DESC") {
+			item.Desc = true
+		} else {
+			p.acceptWord("ASC")
+		}
+		items = append(items, item)
+		if !p.acceptSymbol(",") {
+			return items, nil
+		}
+	}
+}
+
+// LIMIT count, the count being a number or a parameter.
+func (p *stmtParser) parseLimit() (Expr, error) {
+	if !p.acceptWord("LIMIT") {
+		return nil, nil
+	}
+	return p.parseValue()
+}
+
+// parseReturning parses the optional RETURNING clause of INSERT, UPDATE and
+// DELETE statements.
+// RETURNING * | value [[AS] alias], ...
+func (p *stmtParser) parseReturning() ([]SelectItem, error) {
+	if !p.acceptWord("RETURNING") {
+		return nil, nil
+	}
+	var items []SelectItem
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if !p.acceptSymbol(",") {
+			return items, nil
+		}
+	}
+}
+
+// parseSelectItem parses * or a value followed by its optional alias.
+func (p *stmtParser) parseSelectItem() (SelectItem, error) {
+	if star, ok := p.acceptStar(); ok {
+		return SelectItem{Expr: star}, nil
+	}
+	value, err := p.parseExpr()
+	if err != nil {
+		return SelectItem{}, err
+	}
+	item := SelectItem{Expr: value}
+	if p.acceptKeyword("AS") {
+		alias, ok := p.acceptIdentifier()
+		if !ok {
+			return SelectItem{}, p.unexpected("an alias")
+		}
+		item.Alias = alias
+	} else if alias, ok := p.acceptIdentifier(); ok {
+		item.Alias = alias
+	}
+	return item, nil
+}
+
+// acceptStar reads the star of a select item, ok is false when the item is
+// not one.
+// *, table_name.* or schema_name.table_name.*
+func (p *stmtParser) acceptStar() (star *Star, ok bool) {
+	qualifiers := 0
+	for {
+		name, dot := p.peekAt(2*qualifiers), p.peekAt(2*qualifiers+1)
+		if name.Type != IdentifierToken && name.Type != QuotedIdentifierToken || dot.Type != PunctuationToken || dot.Value != "." {
+			break
+		}
+		qualifiers++
+	}
+	if tok := p.peekAt(2 * qualifiers); qualifiers > 2 || tok.Type != OperatorToken || tok.Value != "*" {
+		return nil, false
+	}
+
+	star = &Star{}
+	var parts []Identifier
+	for i := 0; i < qualifiers; i++ {
+		id, _ := p.acceptIdentifier()
+		p.acceptSymbol(".")
+		parts = append(parts, id)
+	}
+	p.acceptOperator("*")
+	if qualifiers > 0 {
+		star.Table.Name = parts[qualifiers-1]
+	}
+	if qualifiers > 1 {
+		star.Table.Schema = parts[0]
+	}
+	p.attachComments(&star.Trivia)
+	return star, true
+}
+
+// parseAssignments parses the items of a SET clause in the order they were
+// written, DEFAULT standing for the default value of the column.
+// col1 = value1, (col2, col3) = (value2, value3), ...
+func (p *stmtParser) parseAssignments(stmtType string) ([]Assignment, error) {
+	var set []Assignment
+	for {
+		var assignment Assignment
+		row := p.acceptSymbol("(")
+		for {
+			column, ok, err := p.parseColumnRef()
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, fmt.Errorf("missing columns in %s statement", stmtType)
+			}
+			assignment.Columns = append(assignment.Columns, column)
+			if !row || !p.acceptSymbol(",") {
+				break
+			}
+		}
+		if row {
+			if err := p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+		}
+		if !p.acceptOperator("=") {
+			return nil, fmt.Errorf("invalid column value pair in %s statement", stmtType)
+		}
+
+		if row {
+			if err := p.expectSymbol("("); err != nil {
+				return nil, err
+			}
+		}
+		for {
+			value, err := p.parseAssignedValue()
+			if err != nil {
+				return nil, err
+			}
+			assignment.Values = append(assignment.Values, value)
+			if !row || !p.acceptSymbol(",") {
+				break
+			}
+		}
+		if row {
+			if err := p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+		}
+
+		p.attachComments(&assignment.Trivia)
+		set = append(set, assignment)
+		if !p.acceptSymbol(",") {
+			return set, nil
+		}
+	}
+}
+
+// parseAssignedValue parses the value of a column, DEFAULT included.
+func (p *stmtParser) parseAssignedValue() (Expr, error) {
+	if p.acceptKeyword("DEFAULT") {
+		def := &Default{}
+		p.attachComments(&def.Trivia)
+		return def, nil
+	}
+	return p.parseExpr()
+}
+
+// DELETE FROM table_name [USING table2, ...] [WHERE condition] [ORDER BY ...] [LIMIT n] [RETURNING ...];
+func (p *stmtParser) parseDelete() (*DeleteStmt, error) {
+	stmt := &DeleteStmt{}
+
+	if err := p.expectKeyword("FROM"); err != nil {
+		return nil, err
+	}
+	table, err := p.parseTableName("DELETE")
+	if err != nil {
+		return nil, err
+	}
+	stmt.Table = table
+
+	if p.acceptWord("USING") {
+		if !p.dialect.DeleteUsing {
+			return nil, fmt.Errorf("syntax error: %s does not support USING in DELETE statements", p.dialect.Name)
+		}
+		for {
+			table, err := p.parseTableName("DELETE")
+			if err != nil {
+				return nil, err
+			}
+			stmt.Using = append(stmt.Using, table)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+
+	stmt.Where, err = p.parseWhere()
+	if err != nil {
+		return nil, err
+	}
+	stmt.OrderBy, stmt.Limit, err = p.parseWriteOrderLimit("DELETE")
+	if err != nil {
+		return nil, err
+	}
+	if len(stmt.Using) > 0 && (stmt.OrderBy != nil || stmt.Limit != nil) {
+		return nil, errors.New("syntax error: ORDER BY and LIMIT can not be used in multiple-table DELETE statements")
+	}
+	stmt.Returning, err = p.parseReturning()
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// DROP TABLE table_name; or DROP INDEX index_name;
+// DROP SCHEMA | DATABASE [IF EXISTS] schema_name [CASCADE | RESTRICT];
+func (p *stmtParser) parseDrop() (Statement, error) {
+	if p.acceptWord("SCHEMA") || p.acceptWord("DATABASE") {
+		return p.parseDropSchema()
+	}
+	stmt := &Drop{}
+
+	switch {
+	case p.acceptKeyword("TABLE"):
+		table, err := p.parseTableName("DROP")
+		if err != nil {
+			return nil, errors.New("invalid DROP statement")
+		}
+		stmt.Table = table
+	case p.acceptKeyword("INDEX"):
+		index, ok := p.acceptIdentifier()
+		if !ok {
+			return nil, errors.New("invalid DROP statement")
+		}
+		stmt.Index = index
+	case p.acceptWord("VIEW"):
+		view, err := p.parseTableName("DROP VIEW")
+		if err != nil {
+			return nil, err
+		}
+		stmt.View = view
+	default:
+		return nil, errors.New("invalid DROP statement")
+	}
+	return stmt, nil
+}
+
+// CREATE TABLE table_name (col1, col2, ...);
+// CREATE INDEX index_name ON table_name (col1, col2, ...);
+// CREATE [OR REPLACE] VIEW view_name [(col1, col2, ...)] AS SELECT ...;
+// CREATE SCHEMA | DATABASE [IF NOT EXISTS] schema_name;
+func (p *stmtParser) parseCreate() (Statement, error) {
+	if p.acceptWord("SCHEMA") || p.acceptWord("DATABASE") {
+		return p.parseCreateSchema()
+	}
+	if p.acceptKeyword("OR") {
+		if err := p.expectWord("REPLACE"); err != nil {
+			return nil, err
+		}
+		return p.parseCreateView(true)
+	}
+	if tok := p.peek(); tok.Type == IdentifierToken && tok.Value == "VIEW" {
+		return p.parseCreateView(false)
+	}
+	stmt := &Create{}
+
+	switch {
+	case p.acceptKeyword("TABLE"):
+		table, err := p.parseTableName("CREATE")
+		if err != nil {
+			return nil, err
+		}
+		stmt.Table = table
+	case p.acceptKeyword("INDEX"):
+		index, ok := p.acceptIdentifier()
+		if !ok {
+			return nil, errors.New("invalid CREATE statement :(")
+		}
+		stmt.Index = index
+		if err := p.expectKeyword("ON"); err != nil {
+			return nil, err
+		}
+		table, err := p.parseTableName("CREATE")
+		if err != nil {
+			return nil, err
+		}
+		stmt.Table = table
+	default:
+		return nil, errors.New("invalid CREATE statement :(")
+	}
+
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	columns, err := p.parseIdentifierList("column", "CREATE")
+	if err != nil {
+		return nil, err
+	}
+	stmt.Columns = columns
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseCreateView parses a CREATE VIEW statement from the VIEW word.
+func (p *stmtParser) parseCreateView(orReplace bool) (*CreateView, error) {
+	if err := p.expectWord("VIEW"); err != nil {
+		return nil, err
+	}
+	stmt := &CreateView{OrReplace: orReplace}
+	name, err := p.parseTableName("CREATE VIEW")
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = name
+	if p.acceptSymbol("(") {
+		stmt.Columns, err = p.parseIdentifierList("column", "CREATE VIEW")
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expectKeyword("AS"); err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword("SELECT"); err != nil {
+		return nil, err
+	}
+	stmt.Select, err = p.parseSelect()
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// TRUNCATE [TABLE] table_name, ... [RESTART IDENTITY | CONTINUE IDENTITY] [CASCADE | RESTRICT];
+func (p *stmtParser) parseTruncate() (*TruncateStmt, error) {
+	stmt := &TruncateStmt{}
+	p.acceptKeyword("TABLE")
+	for {
+		table, err := p.parseTableName("TRUNCATE")
+		if err != nil {
+			return nil, err
+		}
+		stmt.Tables = append(stmt.Tables, table)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	switch {
+	case p.acceptWord("RESTART"):
+		if err := p.expectWord("IDENTITY"); err != nil {
+			return nil, err
+		}
+		stmt.RestartIdentity = true
+	case p.acceptWord("CONTINUE"):
+		if err := p.expectWord("IDENTITY"); err != nil {
+			return nil, err
+		}
+	}
+	if !p.acceptWord("RESTRICT") {
+		stmt.Cascade = p.acceptWord("CASCADE")
+	}
+	return stmt, nil
+}
+
+// PREPARE name [(type, ...)] AS statement;
+func (p *stmtParser) parsePrepare() (*PrepareStmt, error) {
+	stmt := &PrepareStmt{}
+	name, ok := p.acceptIdentifier()
+	if !ok {
+		return nil, p.unexpected("a prepared statement name")
+	}
+	stmt.Name = name
+	if p.acceptSymbol("(") {
+		for {
+			typeName, err := p.parseTypeName()
+			if err != nil {
+				return nil, err
+			}
+			stmt.Types = append(stmt.Types, typeName)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expectKeyword("AS"); err != nil {
+		return nil, err
+	}
+	inner, err := p.parseStatementBody()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Stmt = inner
+	return stmt, nil
+}
+
+// EXECUTE name [(value, ...)];
+func (p *stmtParser) parseExecute() (*ExecuteStmt, error) {
+	stmt := &ExecuteStmt{}
+	name, ok := p.acceptIdentifier()
+	if !ok {
+		return nil, p.unexpected("a prepared statement name")
+	}
+	stmt.Name = name
+	if !p.acceptSymbol("(") {
+		return stmt, nil
+	}
+	for {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Args = append(stmt.Args, arg)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// DEALLOCATE [PREPARE] name | ALL;
+func (p *stmtParser) parseDeallocate() (*DeallocateStmt, error) {
+	stmt := &DeallocateStmt{}
+	p.acceptWord("PREPARE")
+	if p.acceptWord("ALL") {
+		stmt.All = true
+		return stmt, nil
+	}
+	name, ok := p.acceptIdentifier()
+	if !ok {
+		return nil, p.unexpected("a prepared statement name")
+	}
+	stmt.Name = name
+	return stmt, nil
+}
+
+// GRANT privilege [(column, ...)], ... ON [TABLE] table_name, ... TO grantee, ... [WITH GRANT OPTION];
+// GRANT role, ... TO grantee, ... [WITH ADMIN OPTION];
+// REVOKE [GRANT OPTION FOR] privilege [(column, ...)], ... ON [TABLE] table_name, ... FROM grantee, ... [CASCADE | RESTRICT];
+// REVOKE [ADMIN OPTION FOR] role, ... FROM grantee, ... [CASCADE | RESTRICT];
+func (p *stmtParser) parseGrant(revoke bool) (*GrantStmt, error) {
+	stmt := &GrantStmt{Revoke: revoke}
+	option := ""
+	if revoke && p.peekAt(1).Value == "OPTION" {
+		switch {
+		case p.acceptWord("GRANT"):
+			option = "GRANT"
+		case p.acceptWord("ADMIN"):
+			option = "ADMIN"
+		}
+	}
+	if option != "" {
+		if err := p.expectWord("OPTION"); err != nil {
+			return nil, err
+		}
+		if err := p.expectWord("FOR"); err != nil {
+			return nil, err
+		}
+		stmt.GrantOption = true
+	}
+
+	// privileges and roles are told apart by the ON clause that follows
+	// the privileges
+	var items []PrivilegeItem
+	var names []Identifier
+	for {
+		tok := p.peek()
+		name, ok := p.acceptIdentifier()
+		if !ok && tok.Type == KeywordToken {
+			p.next()
+			name = Identifier{Name: tok.Value}
+		} else if !ok {
+			return nil, p.unexpected("a privilege or role")
+		}
+		item := PrivilegeItem{Privilege: Privilege(name.Name)}
+		if item.Privilege == AllPrivileges {
+			p.acceptWord("PRIVILEGES")
+		}
+		if p.acceptSymbol("(") {
+			columns, err := p.parseIdentifierList("column", string(stmt.QueryType()))
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+			item.Columns = columns
+		}
+		items = append(items, item)
+		names = append(names, name)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+
+	if p.acceptKeyword("ON") {
+		for i, item := range items {
+			if names[i].Quoted || (item.Privilege != AllPrivileges && !containsPrivilege(tablePrivileges, item.Privilege)) {
+				return nil, fmt.Errorf("syntax error: %s is not a privilege", names[i])
+			}
+		}
+		stmt.Privileges = items
+		p.acceptKeyword("TABLE")
+		for {
+			table, err := p.parseTableName(string(stmt.QueryType()))
+			if err != nil {
+				return nil, err
+			}
+			stmt.Tables = append(stmt.Tables, table)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	} else {
+		for i, item := range items {
+			if (keywords[names[i].Name] && !names[i].Quoted) || len(item.Columns) > 0 {
+				return nil, fmt.Errorf("syntax error: %s is not a role, privileges are granted ON tables", names[i])
+			}
+		}
+		stmt.Roles = names
+	}
+
+	if revoke {
+		if err := p.expectKeyword("FROM"); err != nil {
+			return nil, err
+		}
+	} else if err := p.expectWord("TO"); err != nil {
+		return nil, err
+	}
+	grantees, err := p.parseIdentifierList("grantee", string(stmt.QueryType()))
+	if err != nil {
+		return nil, err
+	}
+	stmt.Grantees = grantees
+
+	if revoke {
+		if !p.acceptWord("RESTRICT") {
+			stmt.Cascade = p.acceptWord("CASCADE")
+		}
+		if (option == "ADMIN" && stmt.Roles == nil) || (option == "GRANT" && stmt.Roles != nil) {
+			return nil, fmt.Errorf("syntax error: %s OPTION FOR does not apply here", option)
+		}
+		return stmt, nil
+	}
+	if p.acceptWord("WITH") {
+		option = "GRANT"
+		if stmt.Roles != nil {
+			option = "ADMIN"
+		}
+		if err := p.expectWord(option); err != nil {
+			return nil, err
+		}
+		if err := p.expectWord("OPTION"); err != nil {
+			return nil, err
+		}
+		stmt.GrantOption = true
+	}
+	return stmt, nil
+}
+
+// parseCreateSchema parses a CREATE SCHEMA statement after its SCHEMA word.
+func (p *stmtParser) parseCreateSchema() (*CreateSchema, error) {
+	stmt := &CreateSchema{}
+	if p.acceptWord("IF") {
+		if err := p.expectKeyword("NOT"); err != nil {
+			return nil, err
+		}
+		if err := p.expectWord("EXISTS"); err != nil {
+			return nil, err
+		}
+		stmt.IfNotExists = true
+	}
+	name, ok := p.acceptIdentifier()
+	if !ok {
+		return nil, p.unexpected("a schema name")
+	}
+	stmt.Name = name
+	return stmt, nil
+}
+
+// parseDropSchema parses a DROP SCHEMA statement after its SCHEMA word.
+func (p *stmtParser) parseDropSchema() (*DropSchema, error) {
+	stmt := &DropSchema{}
+	if p.acceptWord("IF") {
+		if err := p.expectWord("EXISTS"); err != nil {
+			return nil, err
+		}
+		stmt.IfExists = true
+	}
+	name, ok := p.acceptIdentifier()
+	if !ok {
+		return nil, p.unexpected("a schema name")
+	}
+	stmt.Name = name
+	if !p.acceptWord("RESTRICT") {
+		stmt.Cascade = p.acceptWord("CASCADE")
+	}
+	return stmt, nil
+}
+
+// USE schema_name;
+func (p *stmtParser) parseUse() (*UseStmt, error) {
+	name, ok := p.acceptIdentifier()
+	if !ok {
+		return nil, p.unexpected("a schema name")
+	}
+	return &UseStmt{Schema: name}, nil
+}
+
+// SET [SESSION | LOCAL] search_path TO | = schema_name, ... | DEFAULT;
+func (p *stmtParser) parseSet() (*SetStmt, error) {
+	stmt := &SetStmt{}
+	if !p.acceptWord("SESSION") {
+		p.acceptWord("LOCAL")
+	}
+	name, ok := p.acceptIdentifier()
+	if !ok {
+		return nil, p.unexpected("a setting name")
+	}
+	stmt.Variable = name
+	if !p.acceptWord("TO") && !p.acceptOperator("=") {
+		return nil, p.unexpected("TO")
+	}
+	if p.acceptKeyword("DEFAULT") {
+		return stmt, nil
+	}
+	for {
+		// schema names may be written as strings: SET search_path TO 'sales'
+		if tok := p.peek(); tok.Type == StringToken {
+			lit, _, err := p.parseLiteral()
+			if err != nil {
+				return nil, err
+			}
+			value, ok := lit.Value.(string)
+			if !ok || value == "" {
+				return nil, fmt.Errorf("syntax error: invalid schema name %s", lit.Raw)
+			}
+			stmt.Values = append(stmt.Values, Identifier{Name: value, Quoted: true})
+		} else if value, ok := p.acceptIdentifier(); ok {
+			stmt.Values = append(stmt.Values, value)
+		} else {
+			return nil, p.unexpected("a schema name")
+		}
+		if !p.acceptSymbol(",") {
+			return stmt, nil
+		}
+	}
+}
+
+// SHOW TABLES;
+// SHOW COLUMNS FROM | IN table_name;
+// SHOW INDEX | INDEXES | KEYS FROM | IN table_name;
+// DESCRIBE | DESC table_name;
+func (p *stmtParser) parseShow(first string) (*ShowStmt, error) {
+	stmt := &ShowStmt{}
+	if first != "SHOW" {
+		stmt.Object, stmt.Describe = ShowColumns, true
+		table, err := p.parseTableName(first)
+		if err != nil {
+			return nil, err
+		}
+		stmt.Table = table
+		return stmt, nil
+	}
+
+	switch {
+	case p.acceptWord("TABLES"):
+		stmt.Object = ShowTables
+		return stmt, nil
+	case p.acceptWord("COLUMNS"):
+		stmt.Object = ShowColumns
+	case p.acceptWord("INDEX"), p.acceptWord("INDEXES"), p.acceptWord("KEYS"):
+		stmt.Object = ShowIndexes
+	default:
+		return nil, p.unexpected("TABLES, COLUMNS or INDEXES")
+	}
+	if !p.acceptKeyword("FROM") && !p.acceptWord("IN") {
+		return nil, p.unexpected("FROM")
+	}
+	table, err := p.parseTableName("SHOW")
+	if err != nil {
+		return nil, err
+	}
+	stmt.Table = table
+	return stmt, nil
+}
+
+// BEGIN [TRANSACTION | WORK] [mode, ...];
+// START TRANSACTION [mode, ...];
+// COMMIT [TRANSACTION | WORK];
+// ROLLBACK [TRANSACTION | WORK] [TO [SAVEPOINT] name];
+// SAVEPOINT name;
+// RELEASE [SAVEPOINT] name;
+func (p *stmtParser) parseTransaction(first string) (*TransactionStmt, error) {
+	stmt := &TransactionStmt{Action: TransactionAction(first)}
+	switch first {
+	case "BEGIN", "START":
+		stmt.Action = BeginTransaction
+		if first == "START" {
+			if err := p.expectWord("TRANSACTION"); err != nil {
+				return nil, err
+			}
+		} else if !p.acceptWord("TRANSACTION") {
+			p.acceptWord("WORK")
+		}
+		if err := p.parseTransactionModes(stmt); err != nil {
+			return nil, err
+		}
+		return stmt, nil
+
+	case "COMMIT", "ROLLBACK":
+		if !p.acceptWord("TRANSACTION") {
+			p.acceptWord("WORK")
+		}
+		if first == "COMMIT" || !p.acceptWord("TO") {
+			return stmt, nil
+		}
+		stmt.Action = RollbackToSavepoint
+		p.acceptWord("SAVEPOINT")
+
+	case "RELEASE":
+		p.acceptWord("SAVEPOINT")
+	}
+
+	name, ok := p.acceptIdentifier()
+	if !ok {
+		return nil, p.unexpected("a savepoint name")
+	}
+	stmt.Savepoint = name
+	return stmt, nil
+}
+
+// parseTransactionModes parses the modes of a BEGIN statement.
+// ISOLATION LEVEL level | READ ONLY | READ WRITE, ...
+func (p *stmtParser) parseTransactionModes(stmt *TransactionStmt) error {
+	comma := false
+	for {
+		switch {
+		case p.acceptWord("ISOLATION"):
+			if err := p.expectWord("LEVEL"); err != nil {
+				return err
+			}
+			if stmt.Isolation != "" {
+				return errors.New("syntax error: the isolation level is specified more than once")
+			}
+			switch {
+			case p.acceptWord("SERIALIZABLE"):
+				stmt.Isolation = "SERIALIZABLE"
+			case p.acceptWord("REPEATABLE"):
+				if err := p.expectWord("READ"); err != nil {
+					return err
+				}
+				stmt.Isolation = "REPEATABLE READ"
+			case p.acceptWord("READ"):
+				switch {
+				case p.acceptWord("COMMITTED"):
+					stmt.Isolation = "READ COMMITTED"
+				case p.acceptWord("UNCOMMITTED"):
+					stmt.Isolation = "READ UNCOMMITTED"
+				default:
+					return p.unexpected("COMMITTED or UNCOMMITTED")
+				}
+			default:
+				return p.unexpected("an isolation level")
+			}
+		case p.acceptWord("READ"):
+			switch {
+			case p.acceptWord("ONLY"):
+				stmt.ReadOnly = true
+			case p.acceptWord("WRITE"):
+				stmt.ReadWrite = true
+			default:
+				return p.unexpected("ONLY or WRITE")
+			}
+			if stmt.ReadOnly && stmt.ReadWrite {
+				return errors.New("syntax error: a transaction can not be both READ ONLY and READ WRITE")
+			}
+		case comma:
+			return p.unexpected("a transaction mode")
+		default:
+			return nil
+		}
+		comma = p.acceptSymbol(",")
+	}
+}
+
+// WHERE condition
+func (p *stmtParser) parseWhere() (Expr, error) {
+	if !p.acceptKeyword("WHERE") {
+		return nil, nil
+	}
+	if p.atEnd() {
+		return nil, errors.New("missing condition in WHERE clause")
+	}
+	return p.parseExpr()
+}
+
+// parseExpr parses an expression, conditions included. From the loosest to
+// the tightest, the operators bind as OR, AND, NOT, the predicates, the
+// additive operators, the multiplicative ones and the signs.
+func (p *stmtParser) parseExpr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &LogicalExpr{Left: left, Operator: "OR", Right: right}
+	}
+	return left, nil
+}
+
+// expr AND expr
+func (p *stmtParser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptKeyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &LogicalExpr{Left: left, Operator: "AND", Right: right}
+	}
+	return left, nil
+}
+
+// NOT expr
+func (p *stmtParser) parseNot() (Expr, error) {
+	if p.acceptKeyword("NOT") {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &NotExpr{Expr: expr}, nil
+	}
+	return p.parsePredicate()
+}
+
+// parsePredicate parses a value optionally followed by a comparison or a
+// predicate. The bounds of BETWEEN are values, so that the AND separating
+// them is not taken for the logical one.
+// expr op expr
+// expr IS [NOT] NULL, expr IS [NOT] DISTINCT FROM expr
+// expr [NOT] IN (expr, ...), expr [NOT] BETWEEN expr AND expr
+// expr [NOT] LIKE | ILIKE expr [ESCAPE expr]
+func (p *stmtParser) parsePredicate() (Expr, error) {
+	left, err := p.parseArith()
+	if err != nil {
+		return nil, err
+	}
+
+	if op := p.peek(); op.Type == OperatorToken && comparisonOperators[op.Value] {
+		p.next()
+		right, err := p.parseArith()
+		if err != nil {
+			return nil, err
+		}
+		comparison := &Comparison{Left: left, Operator: op.Value, Right: right}
+		p.attachComments(&comparison.Trivia)
+		return comparison, nil
+	}
+
+	if p.acceptWord("IS") {
+		negated := p.acceptKeyword("NOT")
+		if p.acceptKeyword("NULL") {
+			isNull := &IsNullExpr{Expr: left, Not: negated}
+			p.attachComments(&isNull.Trivia)
+			return isNull, nil
+		}
+		if !p.acceptWord("DISTINCT") {
+			return nil, p.unexpected("NULL or DISTINCT FROM")
+		}
+		if err := p.expectKeyword("FROM"); err != nil {
+			return nil, err
+		}
+		right, err := p.parseArith()
+		if err != nil {
+			return nil, err
+		}
+		distinct := &IsDistinctExpr{Left: left, Not: negated, Right: right}
+		p.attachComments(&distinct.Trivia)
+		return distinct, nil
+	}
+
+	negated := false
+	if tok := p.peekAt(1); p.peek().Type == KeywordToken && p.peek().Value == "NOT" && tok.Type == IdentifierToken &&
+		(tok.Value == "IN" || tok.Value == "BETWEEN" || tok.Value == "LIKE" || tok.Value == "ILIKE") {
+		p.next()
+		negated = true
+	}
+
+	switch {
+	case p.acceptWord("IN"):
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		in := &InExpr{Expr: left, Not: negated}
+		for {
+			value, err := p.parseArith()
+			if err != nil {
+				return nil, err
+			}
+			in.List = append(in.List, value)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		p.attachComments(&in.Trivia)
+		return in, nil
+
+	case p.acceptWord("BETWEEN"):
+		low, err := p.parseArith()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("AND"); err != nil {
+			return nil, err
+		}
+		high, err := p.parseArith()
+		if err != nil {
+			return nil, err
+		}
+		between := &BetweenExpr{Expr: left, Not: negated, Low: low, High: high}
+		p.attachComments(&between.Trivia)
+		return between, nil
+
+	case p.peek().Type == IdentifierToken && (p.peek().Value == "LIKE" || p.peek().Value == "ILIKE"):
+		like := &LikeExpr{Expr: left, Not: negated, Operator: p.next().Value}
+		like.Pattern, err = p.parseArith()
+		if err != nil {
+			return nil, err
+		}
+		if p.acceptWord("ESCAPE") {
+			like.Escape, err = p.parseArith()
+			if err != nil {
+				return nil, err
+			}
+		}
+		p.attachComments(&like.Trivia)
+		return like, nil
+	}
+	return left, nil
+}
+
+// comparisonOperators are the operators comparing two values.
+var comparisonOperators = map[string]bool{"=": true, "<>": true, "!=": true, "<": true, ">": true, "<=": true, ">=": true}
+
+// parseValue parses a constant or a column name.
+func (p *stmtParser) parseValue() (Expr, error) {
+	lit, ok, err := p.parseLiteral()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return lit, nil
+	}
+
+	param, ok, err := p.parseParam()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return param, nil
+	}
+
+	col, ok, err := p.parseColumnRef()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return col, nil
+	}
+	return nil, p.unexpected("a value")
+}
+
+// parseArith parses an arithmetic expression of values.
+// expr + expr, expr - expr, expr || expr
+func (p *stmtParser) parseArith() (Expr, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op.Type != OperatorToken || (op.Value != "+" && op.Value != "-" && op.Value != "||") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Left: left, Operator: op.Value, Right: right}
+	}
+}
+
+// expr * expr, expr / expr, expr % expr
+func (p *stmtParser) parseTerm() (Expr, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		if op.Type != OperatorToken || (op.Value != "*" && op.Value != "/" && op.Value != "%") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Left: left, Operator: op.Value, Right: right}
+	}
+}
+
+// -expr, +expr, (expr) or a value
+func (p *stmtParser) parseFactor() (Expr, error) {
+	tok := p.peek()
+	if tok.Type == OperatorToken && (tok.Value == "-" || tok.Value == "+") && p.peekAt(1).Type != NumberToken {
+		p.next()
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryExpr{Operator: tok.Value, Operand: operand}, nil
+	}
+	if p.acceptSymbol("(") {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		paren := &ParenExpr{Expr: expr}
+		p.attachComments(&paren.Trivia)
+		return paren, nil
+	}
+	if p.acceptKeyword("CASE") {
+		return p.parseCase()
+	}
+	if tok.Type == KeywordToken && tok.Value == "VALUES" && p.peekAt(1).Value == "(" {
+		return p.parseInsertedValue()
+	}
+	if tok.Type == IdentifierToken && p.peekAt(1).Type == PunctuationToken && p.peekAt(1).Value == "(" {
+		if tok.Value == "CAST" {
+			return p.parseCast()
+		}
+		return p.parseFuncCall()
+	}
+	return p.parseValue()
+}
+
+// parseInsertedValue parses the VALUES(column) of ON DUPLICATE KEY UPDATE.
+func (p *stmtParser) parseInsertedValue() (Expr, error) {
+	p.next() // VALUES
+	p.next() // (
+	column, ok := p.acceptIdentifier()
+	if !ok {
+		return nil, p.unexpected("a column name")
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	value := &InsertedValue{Column: column}
+	p.attachComments(&value.Trivia)
+	return value, nil
+}
+
+// parseCase parses a CASE expression following CASE.
+// CASE [operand] WHEN expr THEN expr ... [ELSE expr] END
+func (p *stmtParser) parseCase() (Expr, error) {
+	expr := &CaseExpr{}
+	var err error
+	if p.peek().Type != KeywordToken || p.peek().Value != "WHEN" {
+		expr.Operand, err = p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+	}
+	for p.acceptKeyword("WHEN") {
+		var when WhenClause
+		when.When, err = p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("THEN"); err != nil {
+			return nil, err
+		}
+		when.Then, err = p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		expr.Whens = append(expr.Whens, when)
+	}
+	if len(expr.Whens) == 0 {
+		return nil, p.unexpected("WHEN")
+	}
+	if p.acceptKeyword("ELSE") {
+		expr.Else, err = p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expectKeyword("END"); err != nil {
+		return nil, err
+	}
+	p.attachComments(&expr.Trivia)
+	return expr, nil
+}
+
+// parseFuncCall parses a function call.
+// name(arg1, arg2, ...), COUNT(*), COUNT(DISTINCT expr), SUBSTRING(expr FROM start [FOR length])
+func (p *stmtParser) parseFuncCall() (Expr, error) {
+	name, _ := p.acceptIdentifier()
+	p.next() // (
+	call := &FuncCall{Name: name}
+	if err := p.parseFuncArgs(call); err != nil {
+		return nil, err
+	}
+	if p.acceptWord("OVER") {
+		over, err := p.parseOver()
+		if err != nil {
+			return nil, err
+		}
+		call.Over = over
+	}
+	p.attachComments(&call.Trivia)
+	return call, nil
+}
+
+// parseFuncArgs parses the arguments of a function call up to the closing
+// parenthesis.
+func (p *stmtParser) parseFuncArgs(call *FuncCall) error {
+	switch {
+	case p.acceptOperator("*"):
+		call.Star = true
+	case p.acceptSymbol(")"):
+		return nil
+	default:
+		call.Distinct = p.acceptWord("DISTINCT")
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return err
+			}
+			call.Args = append(call.Args, arg)
+			if len(call.Args) == 1 && call.Name.Name == "SUBSTRING" && p.acceptKeyword("FROM") {
+				if err := p.parseSubstringBounds(call); err != nil {
+					return err
+				}
+				break
+			}
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	return p.expectSymbol(")")
+}
+
+// parseOver parses the window following OVER: a window name or a window
+// specification in parentheses.
+func (p *stmtParser) parseOver() (*WindowSpec, error) {
+	if name, ok := p.acceptIdentifier(); ok {
+		spec := &WindowSpec{Ref: name}
+		p.attachComments(&spec.Trivia)
+		return spec, nil
+	}
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	spec, err := p.parseWindowSpec()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// windowClauses are the words starting the clauses of a window
+// specification, any other name is the window it extends.
+var windowClauses = map[string]bool{
+	"PARTITION": true, "ORDER": true, "ROWS": true, "RANGE": true, "GROUPS": true,
+}
+
+// parseWindowSpec parses the inside of the parentheses of a window.
+// [existing_window] [PARTITION BY expr, ...] [ORDER BY expr [ASC | DESC], ...] [frame]
+func (p *stmtParser) parseWindowSpec() (*WindowSpec, error) {
+	spec := &WindowSpec{}
+	if tok := p.peek(); tok.Type == QuotedIdentifierToken || tok.Type == IdentifierToken && !windowClauses[tok.Value] {
+		spec.Base, _ = p.acceptIdentifier()
+	}
+	if p.acceptWord("PARTITION") {
+		if err := p.expectWord("BY"); err != nil {
+			return nil, err
+		}
+		for {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			spec.PartitionBy = append(spec.PartitionBy, expr)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+	var err error
+	spec.OrderBy, err = p.parseOrderBy()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.Type == IdentifierToken && (tok.Value == "ROWS" || tok.Value == "RANGE" || tok.Value == "GROUPS") {
+		spec.Frame, err = p.parseWindowFrame()
+		if err != nil {
+			return nil, err
+		}
+	}
+	p.attachComments(&spec.Trivia)
+	return spec, nil
+}
+
+// ROWS | RANGE | GROUPS start
+// ROWS | RANGE | GROUPS BETWEEN start AND end
+// followed by [EXCLUDE CURRENT ROW | GROUP | TIES | NO OTHERS]
+func (p *stmtParser) parseWindowFrame() (*WindowFrame, error) {
+	frame := &WindowFrame{Unit: p.next().Value}
+	between := p.acceptWord("BETWEEN")
+	start, err := p.parseFrameBound()
+	if err != nil {
+		return nil, err
+	}
+	frame.Start = start
+	if between {
+		if err := p.expectKeyword("AND"); err != nil {
+			return nil, err
+		}
+		end, err := p.parseFrameBound()
+		if err != nil {
+			return nil, err
+		}
+		frame.End = &end
+	}
+
+	if p.acceptWord("EXCLUDE") {
+		switch {
+		case p.acceptWord("CURRENT"):
+			if err := p.expectWord("ROW"); err != nil {
+				return nil, err
+			}
+			frame.Exclude = "CURRENT ROW"
+		case p.acceptWord("GROUP"):
+			frame.Exclude = "GROUP"
+		case p.acceptWord("TIES"):
+			frame.Exclude = "TIES"
+		case p.acceptWord("NO"):
+			if err := p.expectWord("OTHERS"); err != nil {
+				return nil, err
+			}
+			frame.Exclude = "NO OTHERS"
+		default:
+			return nil, p.unexpected("CURRENT ROW, GROUP, TIES or NO OTHERS")
+		}
+	}
+	return frame, nil
+}
+
+// UNBOUNDED PRECEDING | offset PRECEDING | CURRENT ROW | offset FOLLOWING | UNBOUNDED FOLLOWING
+func (p *stmtParser) parseFrameBound() (FrameBound, error) {
+	switch {
+	case p.acceptWord("UNBOUNDED"):
+		switch {
+		case p.acceptWord("PRECEDING"):
+			return FrameBound{Type: UnboundedPreceding}, nil
+		case p.acceptWord("FOLLOWING"):
+			return FrameBound{Type: UnboundedFollowing}, nil
+		}
+		return FrameBound{}, p.unexpected("PRECEDING or FOLLOWING")
+	case p.acceptWord("CURRENT"):
+		return FrameBound{Type: CurrentRow}, p.expectWord("ROW")
+	}
+
+	// the offset stops before the AND of BETWEEN
+	offset, err := p.parseArith()
+	if err != nil {
+		return FrameBound{}, err
+	}
+	switch {
+	case p.acceptWord("PRECEDING"):
+		return FrameBound{Type: Preceding, Offset: offset}, nil
+	case p.acceptWord("FOLLOWING"):
+		return FrameBound{Type: Following, Offset: offset}, nil
+	}
+	return FrameBound{}, p.unexpected("PRECEDING or FOLLOWING")
+}
+
+// parseSubstringBounds parses the bounds of SUBSTRING(expr FROM start [FOR length])
+// following FROM.
+func (p *stmtParser) parseSubstringBounds(call *FuncCall) error {
+	call.Keywords = true
+	start, err := p.parseExpr()
+	if err != nil {
+		return err
+	}
+	call.Args = append(call.Args, start)
+	if p.acceptWord("FOR") {
+		length, err := p.parseExpr()
+		if err != nil {
+			return err
+		}
+		call.Args = append(call.Args, length)
+	}
+	return nil
+}
+
+// CAST(expr AS type)
+func (p *stmtParser) parseCast() (Expr, error) {
+	p.next() // CAST
+	p.next() // (
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectKeyword("AS"); err != nil {
+		return nil, err
+	}
+	typeName, err := p.parseTypeName()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	cast := &CastExpr{Expr: expr, TypeName: typeName}
+	p.attachComments(&cast.Trivia)
+	return cast, nil
+}
+
+// parseTypeName parses a type name, made of words and an optional length or
+// precision: INT, VARCHAR(20), DOUBLE PRECISION, TIMESTAMP(3) WITH TIME ZONE
+func (p *stmtParser) parseTypeName() (string, error) {
+	var parts []string
+	for {
+		tok := p.peek()
+		switch {
+		case tok.Type == IdentifierToken:
+			p.next()
+			parts = append(parts, tok.Value)
+			continue
+		case tok.Type == PunctuationToken && tok.Value == "(" && len(parts) > 0:
+			p.next()
+			var args []string
+			for {
+				size := p.peek()
+				if size.Type != NumberToken {
+					return "", p.unexpected("a number")
+				}
+				p.next()
+				args = append(args, size.Value)
+				if !p.acceptSymbol(",") {
+					break
+				}
+			}
+			if err := p.expectSymbol(")"); err != nil {
+				return "", err
+			}
+			parts[len(parts)-1] += "(" + strings.Join(args, ", ") + ")"
+			continue
+		}
+		break
+	}
+	if len(parts) == 0 {
+		return "", p.unexpected("a type name")
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// parseParam parses a bind parameter, ? parameters are numbered in order of
+// appearance and can not be mixed with $n ones.
+func (p *stmtParser) parseParam() (param *Param, ok bool, err error) {
+	tok := p.peek()
+	if tok.Type != ParamToken {
+		return nil, false, nil
+	}
+	p.next()
+
+	param = &Param{Raw: tok.Value}
+	switch tok.Value[0] {
+	case '?':
+		p.positional++
+		param.Index = p.positional
+	case '$':
+		p.numbered = true
+		param.Index, err = strconv.Atoi(tok.Value[1:])
+		if err != nil || param.Index == 0 {
+			return nil, true, fmt.Errorf("invalid parameter %s", tok.Value)
+		}
+	default:
+		param.Name = tok.Value[1:]
+	}
+	if p.positional > 0 && p.numbered {
+		return nil, true, errors.New("syntax error: ? and $n parameters can not be mixed")
+	}
+
+	p.attachComments(&param.Trivia)
+	return param, true, nil
+}
+
+// parseLiteral parses a constant, ok is false when the next tokens do not
+// start one.
+func (p *stmtParser) parseLiteral() (lit *Literal, ok bool, err error) {
+	tok := p.peek()
+	switch {
+	case tok.Type == StringToken:
+		p.next()
+		lit, err = newStringLiteral(tok.Value, p.dialect.BackslashEscapes)
+
+	case tok.Type == NumberToken:
+		p.next()
+		lit, err = newNumberLiteral(tok.Value)
+
+	case tok.Type == OperatorToken && (tok.Value == "-" || tok.Value == "+") && p.peekAt(1).Type == NumberToken:
+		// signed number
+		p.next()
+		lit, err = newNumberLiteral(p.next().Value)
+		if err == nil && tok.Value == "-" {
+			lit.negate()
+		} else if err == nil {
+			lit.Raw = "+" + lit.Raw
+		}
+
+	case tok.Type == KeywordToken && (tok.Value == "TRUE" || tok.Value == "FALSE"):
+		p.next()
+		lit = &Literal{Kind: BooleanLiteral, Raw: tok.Value, Value: tok.Value == "TRUE"}
+
+	case tok.Type == KeywordToken && tok.Value == "NULL":
+		p.next()
+		lit = &Literal{Kind: NullLiteral, Raw: tok.Value}
+
+	case tok.Type == IdentifierToken && typedLiteralTypes[tok.Value] && p.peekAt(1).Type == StringToken:
+		// DATE '2024-01-01'
+		p.next()
+		var str *Literal
+		str, err = newStringLiteral(p.next().Value, p.dialect.BackslashEscapes)
+		if err == nil {
+			lit, err = newTypedLiteral(tok.Value, str)
+		}
+
+	default:
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, true, err
+	}
+	p.attachComments(&lit.Trivia)
+	return lit, true, nil
+}
+
+// parseTableName reads the table name of a statement, stmtType is used in
+// the error messages.
+// table_name or schema_name.table_name
+func (p *stmtParser) parseTableName(stmtType string) (TableName, error) {
+	parts, ok, err := p.parseQualifiedName(2)
+	if err != nil {
+		return TableName{}, err
+	}
+	if !ok {
+		if p.atEnd() || p.peek().Type == KeywordToken {
+			return TableName{}, fmt.Errorf("missing table name in %s statement", stmtType)
+		}
+		return TableName{}, fmt.Errorf("invalid table name in %s statement", stmtType)
+	}
+
+	table := TableName{Name: parts[len(parts)-1]}
+	if len(parts) == 2 {
+		table.Schema = parts[0]
+	}
+	p.attachComments(&table.Trivia)
+	return table, nil
+}
+
+// parseColumnRef reads a column name, ok is false when the next token is
+// not a name.
+// column_name, table_name.column_name or schema_name.table_name.column_name
+func (p *stmtParser) parseColumnRef() (col *ColumnRef, ok bool, err error) {
+	parts, ok, err := p.parseQualifiedName(3)
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+
+	col = &ColumnRef{Column: parts[len(parts)-1]}
+	if len(parts) > 1 {
+		col.Table = parts[len(parts)-2]
+	}
+	if len(parts) > 2 {
+		col.Schema = parts[0]
+	}
+	p.attachComments(&col.Trivia)
+	return col, true, nil
+}
+
+// parseQualifiedName reads a dotted name of at most maxParts parts.
+func (p *stmtParser) parseQualifiedName(maxParts int) (parts []Identifier, ok bool, err error) {
+	id, ok := p.acceptIdentifier()
+	if !ok {
+		return nil, false, nil
+	}
+	parts = append(parts, id)
+
+	for p.acceptSymbol(".") {
+		id, ok := p.acceptIdentifier()
+		if !ok {
+			return nil, true, p.unexpected("a name after \".\"")
+		}
+		parts = append(parts, id)
+	}
+	if len(parts) > maxParts {
+		return nil, true, fmt.Errorf("syntax error: too many dots in name %s", joinIdentifiers(parts))
+	}
+	return parts, true, nil
+}
+
+// parseIdentifierList reads a comma separated list of names.
+func (p *stmtParser) parseIdentifierList(what, stmtType string) ([]Identifier, error) {
+	var names []Identifier
+	for {
+		name, ok := p.acceptIdentifier()
+		if !ok {
+			if len(names) == 0 {
+				return nil, fmt.Errorf("missing %ss in %s statement", what, stmtType)
+			}
+			return nil, fmt.Errorf("invalid %s name in %s statement", what, stmtType)
+		}
+		names = append(names, name)
+		p.attachComments(&p.trivia)
+		if !p.acceptSymbol(",") {
+			return names, nil
+		}
+	}
+}
+
+func (p *stmtParser) peek() Token {
+	return p.tokens[p.pos]
+}
+
+// peekAt returns the token n positions ahead without consuming anything.
+func (p *stmtParser) peekAt(n int) Token {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+// next consumes the current token, the EOF token is never passed.
+func (p *stmtParser) next() Token {
+	tok := p.tokens[p.pos]
+	p.pending = append(p.pending, tok.Comments...)
+	if p.pos < len(p.tokens)-1 {
+		p.pos++
+	} else {
+		p.tokens[p.pos].Comments = nil
+	}
+	return tok
+}
+
+// atEnd reports whether only an optional semicolon is left.
+func (p *stmtParser) atEnd() bool {
+	tok := p.peek()
+	return tok.Type == EOFToken || (tok.Type == PunctuationToken && tok.Value == ";")
+}
+
+func (p *stmtParser) acceptKeyword(keyword string) bool {
+	tok := p.peek()
+	if tok.Type == KeywordToken && tok.Value == keyword {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *stmtParser) expectKeyword(keyword string) error {
+	if !p.acceptKeyword(keyword) {
+		return p.unexpected(keyword)
+	}
+	return nil
+}
+
+// acceptWord accepts a word that is a keyword only where it is expected,
+// such as CONFLICT, and remains usable as an unquoted name elsewhere.
+func (p *stmtParser) acceptWord(word string) bool {
+	tok := p.peek()
+	if (tok.Type == IdentifierToken || tok.Type == KeywordToken) && tok.Value == word {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *stmtParser) expectWord(word string) error {
+	if !p.acceptWord(word) {
+		return p.unexpected(word)
+	}
+	return nil
+}
+
+func (p *stmtParser) acceptSymbol(symbol string) bool {
+	tok := p.peek()
+	if tok.Type == PunctuationToken && tok.Value == symbol {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *stmtParser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return p.unexpected(fmt.Sprintf("%q", symbol))
+	}
+	return nil
+}
+
+func (p *stmtParser) acceptOperator(op string) bool {
+	tok := p.peek()
+	if tok.Type == OperatorToken && tok.Value == op {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// acceptIdentifier reads a single quoted or unquoted name.
+func (p *stmtParser) acceptIdentifier() (Identifier, bool) {
+	tok := p.peek()
+	switch tok.Type {
+	case IdentifierToken:
+		p.next()
+		return Identifier{Name: tok.Value}, true
+	case QuotedIdentifierToken:
+		p.next()
+		return Identifier{Name: tok.Value, Quoted: true}, true
+	}
+	return Identifier{}, false
+}
+
+func (p *stmtParser) unexpected(expected string) error {
+	tok := p.peek()
+	if tok.Type == EOFToken {
+		return fmt.Errorf("syntax error: expected %s but found the end of the statement", expected)
+	}
+	return fmt.Errorf("syntax error: expected %s but found %q at position %d", expected, tok.Value, tok.Pos)
+}
+
+// takePending returns the comments of the consumed tokens that were not
+// attached to a node yet.
+func (p *stmtParser) takePending() []Comment {
+	comments := p.pending
+	p.pending = nil
+	return comments
+}
+
+// attachComments gives a node that was just parsed the pending comments and
+// the comments following it on the same line.
+func (p *stmtParser) attachComments(trivia *Trivia) {
+	trivia.attach(p.takePending())
+
+	next := &p.tokens[p.pos]
+	i := 0
+	for i < len(next.Comments) && next.Comments[i].Trailing {
+		i++
+	}
+	trivia.attach(next.Comments[:i])
+	next.Comments = next.Comments[i:]
+}
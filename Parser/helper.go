@@ -7,13 +7,28 @@ import (
 
 type TokenType string
 
+const (
+	KeywordToken          TokenType = "keyword"
+	IdentifierToken       TokenType = "identifier"
+	QuotedIdentifierToken TokenType = "quoted identifier"
+	StringToken           TokenType = "string"
+	NumberToken           TokenType = "number"
+	OperatorToken         TokenType = "operator"
+	PunctuationToken      TokenType = "punctuation"
+	ParamToken            TokenType = "parameter"
+	EOFToken              TokenType = "eof"
+)
+
+// The token types of the former tokenizer, which named the tokens after
+// their role in the statement. The lexer no longer produces them.
+//
+// Deprecated: the role of a name is given by the AST of the statement, see
+// ParsedStmt.Stmt.
 const (
 	ColumnToken    TokenType = "column"
 	TableToken     TokenType = "table"
 	ConditionToken TokenType = "condition"
-	KeywordToken   TokenType = "keyword"
 	ValueToken     TokenType = "value"
-	OperatorToken  TokenType = "operator"
 	IndexToken     TokenType = "index"
 )
 
@@ -26,6 +41,18 @@ const (
 	DeleteQuery QueryType = "DELETE"
 	DropQuery   QueryType = "DROP"
 	CreateQuery QueryType = "CREATE"
+
+	TruncateQuery    QueryType = "TRUNCATE"
+	TransactionQuery QueryType = "TRANSACTION" // BEGIN, COMMIT, ROLLBACK, SAVEPOINT, ...
+	ShowQuery        QueryType = "SHOW"        // SHOW and DESCRIBE
+	ExplainQuery     QueryType = "EXPLAIN"
+	GrantQuery       QueryType = "GRANT"
+	RevokeQuery      QueryType = "REVOKE"
+	UseQuery         QueryType = "USE"
+	SetQuery         QueryType = "SET" // SET search_path
+	PrepareQuery     QueryType = "PREPARE"
+	ExecuteQuery     QueryType = "EXECUTE"
+	DeallocateQuery  QueryType = "DEALLOCATE"
 )
 
 func ContainsAll(arr []string, elements []string) (bool, string) {
@@ -44,12 +71,55 @@ func ContainsAll(arr []string, elements []string) (bool, string) {
 	return true, ""
 }
 
+// containsString reports whether the value is an element of arr.
+func containsString(arr []string, value string) bool {
+	for _, element := range arr {
+		if element == value {
+			return true
+		}
+	}
+	return false
+}
+
+// containsNoDuplicates reports whether every element of arr is unique, it
+// returns the first repeated element otherwise.
+func containsNoDuplicates(arr []string) (bool, string) {
+	seen := make(map[string]bool, len(arr))
+	for _, value := range arr {
+		if seen[value] {
+			return false, value
+		}
+		seen[value] = true
+	}
+	return true, ""
+}
+
 func paresQueryType(queryType string) (QueryType, error) {
 	//this should work with upper and lower case
 	queryType = strings.ToUpper(queryType)
 	switch queryType {
-	case "SELECT", "INSERT", "UPDATE", "DELETE", "DROP", "CREATE":
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "DROP", "CREATE", "TRUNCATE":
 		return QueryType(queryType), nil
+	case "BEGIN", "START", "COMMIT", "ROLLBACK", "SAVEPOINT", "RELEASE":
+		return TransactionQuery, nil
+	case "SHOW", "DESCRIBE", "DESC":
+		return ShowQuery, nil
+	case "EXPLAIN":
+		return ExplainQuery, nil
+	case "PREPARE":
+		return PrepareQuery, nil
+	case "EXECUTE":
+		return ExecuteQuery, nil
+	case "DEALLOCATE":
+		return DeallocateQuery, nil
+	case "GRANT":
+		return GrantQuery, nil
+	case "REVOKE":
+		return RevokeQuery, nil
+	case "USE":
+		return UseQuery, nil
+	case "SET":
+		return SetQuery, nil
 	default:
 		return "", errors.New("invalid query type")
 	}
@@ -58,8 +128,8 @@ func paresQueryType(queryType string) (QueryType, error) {
 // func isKeyword(word string) bool {
 // 	//check if the word is a keyword
 // 	word = strings.ToUpper(word)
-// 	return word == "SELECT" || word == "FROM" || word == "WHERE" || word == "INSERT" || word == "UPDATE" || word == "DELETE" || word == "DROP" 
-	
+// 	return word == "SELECT" || word == "FROM" || word == "WHERE" || word == "INSERT" || word == "UPDATE" || word == "DELETE" || word == "DROP"
+
 // }
 
 // func keyWordsToUpperCase(query string) string {
@@ -72,9 +142,3 @@ func paresQueryType(queryType string) (QueryType, error) {
 // 	}
 // 	return strings.Join(words, " ")
 // }
-
-// fucn to refine the name of the field 
-func refineFieldName(name string) string {
-	// remove the ; and the spaces and the commas
-	return strings.Trim(name, ";,()[] ")
-}
\ No newline at end of file
@@ -0,0 +1,48 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInsert(t *testing.T) {
+	runParseTests(t, newTestParser, []parseTest{
+		{name: "single row", sql: "INSERT INTO users (id, name) VALUES (1, 'ann')"},
+		{name: "several rows", sql: "INSERT INTO users (id, name) VALUES (1, 'ann'), (2, 'bob'), (3, NULL)"},
+		{name: "schema column order", sql: "INSERT INTO users VALUES (1, 'ann', 30, 'ann@example.com')"},
+		{name: "expressions", sql: "INSERT INTO orders (id, total) VALUES (1 + 1, 2.5 * 2)"},
+		{name: "default values", sql: "INSERT INTO users DEFAULT VALUES"},
+		{name: "select", sql: "INSERT INTO orders (id, user_id) SELECT id, id FROM users WHERE age > 18"},
+		{name: "select of every column", sql: "INSERT INTO users SELECT * FROM users"},
+		{name: "unknown table", sql: "INSERT INTO nope (id) VALUES (1)", err: "table NOPE does not exist in the schema"},
+		{name: "unknown column", sql: "INSERT INTO users (nope) VALUES (1)", err: "column NOPE does not exist in table USERS"},
+		{name: "duplicate column", sql: "INSERT INTO users (id, id) VALUES (1, 2)", err: "column ID is specified more than once"},
+		{name: "too few values", sql: "INSERT INTO users (id, name) VALUES (1)", err: "values count does not match the columns count in row 1"},
+		{name: "row of several", sql: "INSERT INTO users (id, name) VALUES (1, 'ann'), (2)", err: "values count does not match the columns count in row 2"},
+		{name: "type of a row", sql: "INSERT INTO users (id, age) VALUES (1, 2), (3, 'old')", err: "row 2: value 'old' does not match the type INTEGER of column AGE"},
+		{name: "column in values", sql: "INSERT INTO users (id, age) VALUES (1, id)", err: "column ID can not be used in VALUES"},
+		{name: "select width", sql: "INSERT INTO orders (id, user_id) SELECT id FROM users", err: "SELECT returns 1 columns but 2 columns are inserted"},
+		{name: "select types", sql: "INSERT INTO orders (id, user_id) SELECT id, name FROM users", err: "column 2 of the SELECT is TEXT and does not match the type INTEGER of column USER_ID"},
+		{name: "default values with columns", sql: "INSERT INTO users (id) DEFAULT VALUES", err: "DEFAULT VALUES can not be used with a column list"},
+		{name: "missing rows", sql: "INSERT INTO users (id)", err: "expected VALUES, SELECT or DEFAULT VALUES"},
+	})
+}
+
+func TestInsertStatement(t *testing.T) {
+	parsedStmt := mustParse(t, newTestParser(), "INSERT INTO users (id, name) VALUES (1, 'ann'), (2, 'bob')")
+	stmt := parsedStmt.Stmt.(*InsertStmt)
+	if len(stmt.Rows) != 2 || stmt.Select != nil || stmt.DefaultValues {
+		t.Fatalf("unexpected rows: %+v", stmt)
+	}
+	if want := []string{"ID", "NAME"}; !reflect.DeepEqual(parsedStmt.Columns, want) {
+		t.Fatalf("expected the columns %v, got %v", want, parsedStmt.Columns)
+	}
+
+	parsedStmt = mustParse(t, newTestParser(), "INSERT INTO users SELECT * FROM users")
+	if want := []string{"ID", "NAME", "AGE", "EMAIL"}; !reflect.DeepEqual(parsedStmt.Columns, want) {
+		t.Fatalf("expected the columns %v, got %v", want, parsedStmt.Columns)
+	}
+	if parsedStmt.Stmt.(*InsertStmt).Select == nil {
+		t.Fatal("expected the SELECT of the INSERT")
+	}
+}
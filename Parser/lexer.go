@@ -0,0 +1,356 @@
+package sqlParser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Comment is a -- line or /* block */ comment met in the SQL text. Comments
+// are only kept, as trivia of the AST nodes, when SQLParser.KeepComments is set.
+type Comment struct {
+	Text     string // the comment including its delimiters
+	Block    bool   // true for /* */ comments
+	Trailing bool   // the comment follows a token on the same line
+	Pos      int    // byte offset of the comment in the statement
+}
+
+// keywords are the reserved words of the grammar, they can not be used as
+// unquoted table or column names.
+var keywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true,
+	"INSERT": true, "INTO": true, "VALUES": true, "DEFAULT": true,
+	"UPDATE": true, "SET": true,
+	"DELETE": true,
+	"CREATE": true, "DROP": true, "TABLE": true, "INDEX": true, "ON": true,
+	"AND": true, "OR": true, "NOT": true, "AS": true,
+	"NULL": true, "TRUE": true, "FALSE": true,
+	"CASE": true, "WHEN": true, "THEN": true, "ELSE": true, "END": true,
+}
+
+// operators lists the operator tokens, longest first so that "<=" is not
+// read as "<" followed by "=".
+var operators = []string{
+	"<=", ">=", "<>", "!=", "||",
+	"=", "<", ">", "+", "-", "*", "/", "%",
+}
+
+// lexer breaks a SQL statement down into tokens.
+type lexer struct {
+	src          string
+	pos          int
+	dialect      Dialect
+	keepComments bool
+	lastEnd      int       // end offset of the previous token, -1 before the first one
+	comments     []Comment // comments met since the previous token
+}
+
+func newLexer(sql string, dialect Dialect, keepComments bool) *lexer {
+	return &lexer{src: sql, dialect: dialect, keepComments: keepComments, lastEnd: -1}
+}
+
+// lex returns all the tokens of the statement, the last one being an EOFToken
+// that carries the comments found after the final token.
+func (l *lexer) lex() ([]Token, error) {
+	var tokens []Token
+	for {
+		token, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+		if token.Type == EOFToken {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *lexer) next() (Token, error) {
+	if err := l.skipTrivia(); err != nil {
+		return Token{}, err
+	}
+
+	start := l.pos
+	if start >= len(l.src) {
+		return l.emit(Token{Type: EOFToken, Pos: start}), nil
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.src[start:])
+	switch {
+	case strings.ContainsRune("EeNnXx", r) && l.peekAt(1) == '\'':
+		// E'...', N'...' and X'...' strings
+		l.pos++
+		if err := l.scanString(r == 'E' || r == 'e' || l.dialect.BackslashEscapes); err != nil {
+			return Token{}, err
+		}
+		return l.emit(Token{Type: StringToken, Value: l.src[start:l.pos], Pos: start}), nil
+
+	case isIdentStart(r):
+		for l.pos < len(l.src) {
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			if !isWordRune(r) {
+				break
+			}
+			l.pos += size
+		}
+		word := strings.ToUpper(l.src[start:l.pos])
+		if keywords[word] {
+			return l.emit(Token{Type: KeywordToken, Value: word, Pos: start}), nil
+		}
+		return l.emit(Token{Type: IdentifierToken, Value: word, Pos: start}), nil
+
+	case isDigit(r) || (r == '.' && isDigit(l.peekAt(1))):
+		l.scanDigits()
+		if l.peekAt(0) == '.' {
+			l.pos++
+			l.scanDigits()
+		}
+		// exponent of the scientific notation: 1.5e10, 2E-3
+		if e := l.peekAt(0); e == 'e' || e == 'E' {
+			digits := 1
+			if sign := l.peekAt(1); sign == '+' || sign == '-' {
+				digits = 2
+			}
+			if isDigit(l.peekAt(digits)) {
+				l.pos += digits
+				l.scanDigits()
+			}
+		}
+		return l.emit(Token{Type: NumberToken, Value: l.src[start:l.pos], Pos: start}), nil
+
+	case r == '\'':
+		if err := l.scanString(l.dialect.BackslashEscapes); err != nil {
+			return Token{}, err
+		}
+		return l.emit(Token{Type: StringToken, Value: l.src[start:l.pos], Pos: start}), nil
+
+	case strings.ContainsRune(l.dialect.IdentifierQuotes, r):
+		name, err := l.scanQuotedIdentifier(r)
+		if err != nil {
+			return Token{}, err
+		}
+		return l.emit(Token{Type: QuotedIdentifierToken, Value: name, Pos: start}), nil
+
+	case r == '?':
+		l.pos++
+		return l.emit(Token{Type: ParamToken, Value: "?", Pos: start}), nil
+
+	case r == '$' && l.dialect.DollarQuotes && l.dollarTag() != "":
+		if err := l.scanDollarString(l.dollarTag()); err != nil {
+			return Token{}, err
+		}
+		return l.emit(Token{Type: StringToken, Value: l.src[start:l.pos], Pos: start}), nil
+
+	case r == '$' && isDigit(l.peekAt(1)):
+		// $1 numbered parameter
+		l.pos++
+		l.scanDigits()
+		return l.emit(Token{Type: ParamToken, Value: l.src[start:l.pos], Pos: start}), nil
+
+	case (r == ':' || r == '@') && isIdentStart(l.peekAt(1)):
+		// :name and @name named parameters
+		l.pos++
+		for l.pos < len(l.src) {
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			if !isWordRune(r) {
+				break
+			}
+			l.pos += size
+		}
+		return l.emit(Token{Type: ParamToken, Value: l.src[start:l.pos], Pos: start}), nil
+
+	case strings.ContainsRune("(),;.", r):
+		l.pos++
+		return l.emit(Token{Type: PunctuationToken, Value: string(r), Pos: start}), nil
+	}
+
+	for _, op := range operators {
+		if strings.HasPrefix(l.src[start:], op) {
+			l.pos += len(op)
+			return l.emit(Token{Type: OperatorToken, Value: op, Pos: start}), nil
+		}
+	}
+
+	return Token{}, fmt.Errorf("syntax error: unexpected character %q at position %d", r, start)
+}
+
+// emit hands the pending comments over to the token.
+func (l *lexer) emit(token Token) Token {
+	token.Comments = l.comments
+	l.comments = nil
+	l.lastEnd = l.pos
+	return token
+}
+
+// skipTrivia moves past whitespace and comments, recording the comments
+// when they are to be kept.
+func (l *lexer) skipTrivia() error {
+	for l.pos < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		switch {
+		case unicode.IsSpace(r):
+			l.pos += size
+
+		case strings.HasPrefix(l.src[l.pos:], "--"):
+			start := l.pos
+			end := strings.IndexByte(l.src[start:], '\n')
+			if end < 0 {
+				l.pos = len(l.src)
+			} else {
+				l.pos = start + end
+			}
+			l.addComment(start, false)
+
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			start := l.pos
+			if err := l.scanBlockComment(); err != nil {
+				return err
+			}
+			l.addComment(start, true)
+
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// scanBlockComment moves past a /* */ comment, nesting them when the
+// dialect allows it.
+func (l *lexer) scanBlockComment() error {
+	start := l.pos
+	l.pos += 2
+	depth := 1
+	for l.pos < len(l.src) {
+		switch {
+		case strings.HasPrefix(l.src[l.pos:], "*/"):
+			l.pos += 2
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		case l.dialect.NestedComments && strings.HasPrefix(l.src[l.pos:], "/*"):
+			l.pos += 2
+			depth++
+		default:
+			l.pos++
+		}
+	}
+	return fmt.Errorf("syntax error: unterminated block comment at position %d", start)
+}
+
+func (l *lexer) addComment(start int, block bool) {
+	if !l.keepComments {
+		return
+	}
+	trailing := l.lastEnd >= 0 && !strings.Contains(l.src[l.lastEnd:start], "\n")
+	l.comments = append(l.comments, Comment{
+		Text:     l.src[start:l.pos],
+		Block:    block,
+		Trailing: trailing,
+		Pos:      start,
+	})
+}
+
+// scanString moves past a quoted string, a doubled quote is an escaped one
+// as is a quote following a backslash when backslash escapes are allowed.
+func (l *lexer) scanString(backslashEscapes bool) error {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.src) {
+		if backslashEscapes && l.src[l.pos] == '\\' {
+			l.pos += 2
+			continue
+		}
+		if l.src[l.pos] == '\'' {
+			if l.peekAt(1) == '\'' {
+				l.pos += 2
+				continue
+			}
+			l.pos++
+			return nil
+		}
+		l.pos++
+	}
+	return fmt.Errorf("syntax error: unterminated string at position %d", start)
+}
+
+// dollarTag returns the tag opening a dollar-quoted string at the current
+// position, $$ or $tag$, empty when there is none.
+func (l *lexer) dollarTag() string {
+	end := l.pos + 1
+	for end < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[end:])
+		if r == '$' {
+			return l.src[l.pos : end+1]
+		}
+		// the tag is an identifier, $1 is a parameter
+		if !isWordRune(r) || (end == l.pos+1 && !isIdentStart(r)) {
+			return ""
+		}
+		end += size
+	}
+	return ""
+}
+
+// scanDollarString moves past a dollar-quoted string up to the closing tag,
+// nothing is escaped in its body.
+func (l *lexer) scanDollarString(tag string) error {
+	start := l.pos
+	end := strings.Index(l.src[start+len(tag):], tag)
+	if end < 0 {
+		return fmt.Errorf("syntax error: unterminated dollar-quoted string at position %d", start)
+	}
+	l.pos = start + len(tag) + end + len(tag)
+	return nil
+}
+
+// scanQuotedIdentifier moves past a quoted identifier and returns the name
+// it holds, a doubled closing quote stands for itself.
+func (l *lexer) scanQuotedIdentifier(open rune) (string, error) {
+	start := l.pos
+	closing := byte(closingQuote(open))
+	var sb strings.Builder
+	l.pos++
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		l.pos++
+		if c != closing {
+			sb.WriteByte(c)
+			continue
+		}
+		if l.peekAt(0) == rune(closing) {
+			sb.WriteByte(c)
+			l.pos++
+			continue
+		}
+		if sb.Len() == 0 {
+			return "", fmt.Errorf("syntax error: zero-length quoted identifier at position %d", start)
+		}
+		return sb.String(), nil
+	}
+	return "", fmt.Errorf("syntax error: unterminated quoted identifier at position %d", start)
+}
+
+func (l *lexer) scanDigits() {
+	for isDigit(l.peekAt(0)) {
+		l.pos++
+	}
+}
+
+// peekAt returns the byte n positions ahead, 0 past the end of input.
+func (l *lexer) peekAt(n int) rune {
+	if l.pos+n >= len(l.src) {
+		return 0
+	}
+	return rune(l.src[l.pos+n])
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
@@ -0,0 +1,136 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		sql     string
+		tokens  []Token // the tokens before the EOF one, without positions
+		err     string
+	}{
+		{
+			name: "keywords and identifiers",
+			sql:  "select Name\tfrom\n  users",
+			tokens: []Token{
+				{Type: KeywordToken, Value: "SELECT"}, {Type: IdentifierToken, Value: "NAME"},
+				{Type: KeywordToken, Value: "FROM"}, {Type: IdentifierToken, Value: "USERS"},
+			},
+		},
+		{
+			name: "comments are skipped",
+			sql:  "SELECT -- the id\n id /* of */ FROM users",
+			tokens: []Token{
+				{Type: KeywordToken, Value: "SELECT"}, {Type: IdentifierToken, Value: "ID"},
+				{Type: KeywordToken, Value: "FROM"}, {Type: IdentifierToken, Value: "USERS"},
+			},
+		},
+		{
+			name: "nested block comments",
+			sql:  "SELECT /* a /* b */ c */ 1",
+			tokens: []Token{
+				{Type: KeywordToken, Value: "SELECT"}, {Type: NumberToken, Value: "1"},
+			},
+		},
+		{
+			name:    "block comments do not nest in MySQL",
+			dialect: MySQLDialect,
+			sql:     "SELECT /* a /* b */ 1",
+			tokens: []Token{
+				{Type: KeywordToken, Value: "SELECT"}, {Type: NumberToken, Value: "1"},
+			},
+		},
+		{
+			name: "operators and punctuation",
+			sql:  "a<=b<>c||d.e, (f)",
+			tokens: []Token{
+				{Type: IdentifierToken, Value: "A"}, {Type: OperatorToken, Value: "<="},
+				{Type: IdentifierToken, Value: "B"}, {Type: OperatorToken, Value: "<>"},
+				{Type: IdentifierToken, Value: "C"}, {Type: OperatorToken, Value: "||"},
+				{Type: IdentifierToken, Value: "D"}, {Type: PunctuationToken, Value: "."},
+				{Type: IdentifierToken, Value: "E"}, {Type: PunctuationToken, Value: ","},
+				{Type: PunctuationToken, Value: "("}, {Type: IdentifierToken, Value: "F"},
+				{Type: PunctuationToken, Value: ")"},
+			},
+		},
+		{
+			name: "dollar-quoted strings",
+			sql:  "$$it's$$ $fn$a $$ b$fn$",
+			tokens: []Token{
+				{Type: StringToken, Value: "$$it's$$"}, {Type: StringToken, Value: "$fn$a $$ b$fn$"},
+			},
+		},
+		{name: "unterminated block comment", sql: "SELECT /* a /* b */ 1", err: "unterminated block comment at position 7"},
+		{name: "unterminated string", sql: "SELECT 'abc", err: "unterminated string at position 7"},
+		{name: "unterminated dollar-quoted string", sql: "SELECT $x$abc$$", err: "unterminated dollar-quoted string at position 7"},
+		{name: "unexpected character", sql: "SELECT id # 2", err: `unexpected character '#' at position 10`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parser := newTestParser()
+			if test.dialect.Name != "" {
+				parser.Dialect = test.dialect
+			}
+			tokens, err := parser.Tokenize(test.sql)
+			checkError(t, err, test.err)
+			if test.err != "" {
+				return
+			}
+			if last := tokens[len(tokens)-1]; last.Type != EOFToken {
+				t.Fatalf("expected an EOF token last, got %v", last)
+			}
+			var got []Token
+			for _, token := range tokens[:len(tokens)-1] {
+				got = append(got, Token{Type: token.Type, Value: token.Value})
+			}
+			if !reflect.DeepEqual(got, test.tokens) {
+				t.Fatalf("expected the tokens %v, got %v", test.tokens, got)
+			}
+		})
+	}
+}
+
+func TestTokenPositions(t *testing.T) {
+	tokens, err := newTestParser().Tokenize("SELECT  id\nFROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var positions []int
+	for _, token := range tokens {
+		positions = append(positions, token.Pos)
+	}
+	if want := []int{0, 8, 11, 16, 21}; !reflect.DeepEqual(positions, want) {
+		t.Fatalf("expected the positions %v, got %v", want, positions)
+	}
+}
+
+func TestKeepComments(t *testing.T) {
+	parser := newTestParser()
+	parser.KeepComments = true
+	parsedStmt := mustParse(t, parser, "-- all the users\nSELECT id /* key */ FROM users /* trailing */")
+	stmt := parsedStmt.Stmt.(*SelectStmt)
+	tests := []struct {
+		node     string
+		comments []Comment
+		want     Comment
+	}{
+		{"statement", stmt.Comments, Comment{Text: "-- all the users", Pos: 0}},
+		{"column", stmt.Columns[0].Expr.(*ColumnRef).Comments, Comment{Text: "/* key */", Block: true, Trailing: true, Pos: 27}},
+		{"table", stmt.Tables[0].Comments, Comment{Text: "/* trailing */", Block: true, Trailing: true, Pos: 48}},
+	}
+	for _, test := range tests {
+		if len(test.comments) != 1 || test.comments[0] != test.want {
+			t.Errorf("expected the %s comment %+v, got %+v", test.node, test.want, test.comments)
+		}
+	}
+
+	// without KeepComments they are dropped
+	parsedStmt = mustParse(t, newTestParser(), "-- all the users\nSELECT id FROM users")
+	if comments := parsedStmt.Stmt.(*SelectStmt).Comments; comments != nil {
+		t.Fatalf("expected no comments, got %v", comments)
+	}
+}
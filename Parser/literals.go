@@ -0,0 +1,259 @@
+package sqlParser
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// LiteralKind tells which kind of constant a Literal holds.
+type LiteralKind string
+
+const (
+	StringLiteral  LiteralKind = "string"  // 'text', E'text', N'text', $$text$$
+	BlobLiteral    LiteralKind = "blob"    // X'ff00'
+	IntegerLiteral LiteralKind = "integer" // 42
+	DecimalLiteral LiteralKind = "decimal" // 4.2
+	FloatLiteral   LiteralKind = "float"   // 4.2e10
+	BooleanLiteral LiteralKind = "boolean" // TRUE, FALSE
+	NullLiteral    LiteralKind = "null"    // NULL
+	TypedLiteral   LiteralKind = "typed"   // DATE '2024-01-01'
+)
+
+// Literal is a constant written in the statement.
+//
+// Value holds the decoded constant: a string for string and interval
+// literals, []byte for blobs, int64 for integers, *big.Rat for decimals (and
+// for integers too large for an int64), float64 for floats, bool for
+// booleans, time.Time for dates, times and timestamps and nil for NULL.
+type Literal struct {
+	Trivia
+	Kind     LiteralKind
+	Raw      string // the literal as written in the statement
+	Value    interface{}
+	TypeName string // DATE, TIME, TIMESTAMP or INTERVAL for typed literals
+	National bool   // N'...' string
+}
+
+func (lit *Literal) String() string {
+	return lit.Raw
+}
+
+// Type returns the type of the constant.
+func (lit *Literal) Type() DataType {
+	switch lit.Kind {
+	case StringLiteral:
+		return TextType
+	case BlobLiteral:
+		return BlobType
+	case IntegerLiteral:
+		return IntegerType
+	case DecimalLiteral:
+		return DecimalType
+	case FloatLiteral:
+		return FloatType
+	case BooleanLiteral:
+		return BooleanType
+	case NullLiteral:
+		return NullType
+	case TypedLiteral:
+		return DataType(lit.TypeName)
+	}
+	return UnknownType
+}
+
+// typedLiteralTypes are the type names that can introduce a typed literal.
+var typedLiteralTypes = map[string]bool{
+	"DATE": true, "TIME": true, "TIMESTAMP": true, "INTERVAL": true,
+}
+
+// typedLiteralLayouts lists the accepted formats of the date and time literals.
+var typedLiteralLayouts = map[string][]string{
+	"DATE":      {"2006-01-02"},
+	"TIME":      {"15:04:05.999999999", "15:04"},
+	"TIMESTAMP": {"2006-01-02 15:04:05.999999999", "2006-01-02T15:04:05.999999999", "2006-01-02 15:04", "2006-01-02"},
+}
+
+// newStringLiteral decodes a string token: '...', E'...', N'...', X'...' or
+// $tag$...$tag$.
+func newStringLiteral(raw string, backslashEscapes bool) (*Literal, error) {
+	lit := &Literal{Kind: StringLiteral, Raw: raw}
+
+	body := raw
+	switch body[0] {
+	case '$':
+		tag := raw[:strings.IndexByte(raw[1:], '$')+2]
+		lit.Value = raw[len(tag) : len(raw)-len(tag)]
+		return lit, nil
+	case 'E', 'e':
+		backslashEscapes = true
+		body = body[1:]
+	case 'N', 'n':
+		lit.National = true
+		body = body[1:]
+	case 'X', 'x':
+		data, err := hex.DecodeString(unquote(body[1:]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hexadecimal literal %s", raw)
+		}
+		lit.Kind = BlobLiteral
+		lit.Value = data
+		return lit, nil
+	}
+
+	if !backslashEscapes {
+		lit.Value = unquote(body)
+		return lit, nil
+	}
+	value, err := unescape(body[1 : len(body)-1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid string literal %s: %v", raw, err)
+	}
+	lit.Value = value
+	return lit, nil
+}
+
+// newNumberLiteral decodes an integer, decimal or scientific notation number.
+func newNumberLiteral(raw string) (*Literal, error) {
+	if strings.ContainsAny(raw, "eE") {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", raw)
+		}
+		return &Literal{Kind: FloatLiteral, Raw: raw, Value: value}, nil
+	}
+
+	if !strings.Contains(raw, ".") {
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err == nil {
+			return &Literal{Kind: IntegerLiteral, Raw: raw, Value: value}, nil
+		}
+	}
+
+	// decimals and integers out of the int64 range are kept exact
+	value, ok := new(big.Rat).SetString(raw)
+	if !ok {
+		return nil, fmt.Errorf("invalid number %s", raw)
+	}
+	kind := DecimalLiteral
+	if !strings.Contains(raw, ".") {
+		kind = IntegerLiteral
+	}
+	return &Literal{Kind: kind, Raw: raw, Value: value}, nil
+}
+
+// newTypedLiteral decodes a typed literal such as DATE '2024-01-01'.
+func newTypedLiteral(typeName string, str *Literal) (*Literal, error) {
+	lit := &Literal{Kind: TypedLiteral, Raw: typeName + " " + str.Raw, TypeName: typeName}
+	text := str.Value.(string)
+
+	if typeName == "INTERVAL" {
+		if strings.TrimSpace(text) == "" {
+			return nil, fmt.Errorf("invalid literal %s", lit.Raw)
+		}
+		lit.Value = text
+		return lit, nil
+	}
+
+	for _, layout := range typedLiteralLayouts[typeName] {
+		if value, err := time.Parse(layout, text); err == nil {
+			lit.Value = value
+			return lit, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid literal %s", lit.Raw)
+}
+
+// negate applies a unary minus to a number literal.
+func (lit *Literal) negate() {
+	lit.Raw = "-" + lit.Raw
+	switch value := lit.Value.(type) {
+	case int64:
+		lit.Value = -value
+	case float64:
+		lit.Value = -value
+	case *big.Rat:
+		lit.Value = new(big.Rat).Neg(value)
+	}
+}
+
+// unquote strips the quotes of a string and turns doubled quotes into single ones.
+func unquote(quoted string) string {
+	body := quoted[1 : len(quoted)-1]
+	return strings.ReplaceAll(body, "''", "'")
+}
+
+// unescape decodes the backslash escapes and the doubled quotes of the body
+// of a string.
+func unescape(s string) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\'' && i+1 < len(s) && s[i+1] == '\'' {
+			sb.WriteByte(c)
+			i++
+			continue
+		}
+		if c != '\\' {
+			sb.WriteByte(c)
+			continue
+		}
+		i++
+		if i == len(s) {
+			return "", errors.New("trailing backslash")
+		}
+
+		switch c = s[i]; c {
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'x', 'u', 'U':
+			digits := map[byte]int{'x': 2, 'u': 4, 'U': 8}[c]
+			end := i + 1
+			for end < len(s) && end-i-1 < digits && isHexDigit(s[end]) {
+				end++
+			}
+			if end == i+1 || (c != 'x' && end-i-1 != digits) {
+				return "", fmt.Errorf("invalid escape \\%c", c)
+			}
+			code, _ := strconv.ParseUint(s[i+1:end], 16, 32)
+			if c == 'x' {
+				sb.WriteByte(byte(code))
+			} else {
+				if !utf8.ValidRune(rune(code)) {
+					return "", fmt.Errorf("invalid unicode escape \\%s", s[i:end])
+				}
+				sb.WriteRune(rune(code))
+			}
+			i = end - 1
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			end := i
+			for end < len(s) && end-i < 3 && s[end] >= '0' && s[end] <= '7' {
+				end++
+			}
+			code, _ := strconv.ParseUint(s[i:end], 8, 8)
+			sb.WriteByte(byte(code))
+			i = end - 1
+		default:
+			// \\, \' and any other escaped character stand for themselves
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String(), nil
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(rune(c)) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
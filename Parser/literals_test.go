@@ -0,0 +1,91 @@
+package sqlParser
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLiterals(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		literal string
+		kind    LiteralKind
+		value   interface{}
+		err     string
+	}{
+		{name: "string", literal: "'it''s'", kind: StringLiteral, value: "it's"},
+		{name: "backslash kept", literal: `'a\nb'`, kind: StringLiteral, value: `a\nb`},
+		{name: "escape string", literal: `E'a\nb\'c'`, kind: StringLiteral, value: "a\nb'c"},
+		{name: "MySQL escapes", dialect: MySQLDialect, literal: `'a\tb'`, kind: StringLiteral, value: "a\tb"},
+		{name: "national string", literal: "N'abc'", kind: StringLiteral, value: "abc"},
+		{name: "dollar-quoted string", literal: "$q$it's$q$", kind: StringLiteral, value: "it's"},
+		{name: "blob", literal: "X'ff00'", kind: BlobLiteral, value: []byte{0xff, 0x00}},
+		{name: "integer", literal: "42", kind: IntegerLiteral, value: int64(42)},
+		{name: "negative integer", literal: "-42", kind: IntegerLiteral, value: int64(-42)},
+		{name: "large integer", literal: "123456789012345678901234567890", kind: IntegerLiteral, value: mustRat("123456789012345678901234567890")},
+		{name: "decimal", literal: "4.25", kind: DecimalLiteral, value: mustRat("4.25")},
+		{name: "leading dot", literal: ".5", kind: DecimalLiteral, value: mustRat("0.5")},
+		{name: "float", literal: "1.5e3", kind: FloatLiteral, value: 1500.0},
+		{name: "boolean", literal: "TRUE", kind: BooleanLiteral, value: true},
+		{name: "null", literal: "NULL", kind: NullLiteral, value: nil},
+		{name: "date", literal: "DATE '2024-02-29'", kind: TypedLiteral, value: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "timestamp", literal: "TIMESTAMP '2024-01-02 03:04:05'", kind: TypedLiteral, value: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{name: "interval", literal: "INTERVAL '1 day'", kind: TypedLiteral, value: "1 day"},
+		{name: "invalid hexadecimal", literal: "X'abc'", err: "invalid hexadecimal literal X'abc'"},
+		{name: "unicode escape", literal: `E'\u00e9'`, kind: StringLiteral, value: "é"},
+		{name: "invalid escape", literal: `E'\u12'`, err: `invalid string literal E'\u12': invalid escape \u`},
+		{name: "invalid date", literal: "DATE '2023-02-29'", err: "invalid literal DATE '2023-02-29'"},
+		{name: "empty interval", literal: "INTERVAL ' '", err: "invalid literal INTERVAL ' '"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parser := newTestParser()
+			if test.dialect.Name != "" {
+				parser.Dialect = test.dialect
+			}
+			parsedStmt, err := parser.ParseSQL("SELECT " + test.literal + " AS x FROM users")
+			checkError(t, err, test.err)
+			if test.err != "" {
+				return
+			}
+			lit, ok := parsedStmt.Stmt.(*SelectStmt).Columns[0].Expr.(*Literal)
+			if !ok {
+				t.Fatalf("expected a literal, got %v", parsedStmt.Stmt.(*SelectStmt).Columns[0].Expr)
+			}
+			if lit.Kind != test.kind {
+				t.Fatalf("expected a %s literal, got a %s one", test.kind, lit.Kind)
+			}
+			if rat, ok := test.value.(*big.Rat); ok {
+				if value, ok := lit.Value.(*big.Rat); !ok || value.Cmp(rat) != 0 {
+					t.Fatalf("expected the value %v, got %v", rat, lit.Value)
+				}
+				return
+			}
+			if !reflect.DeepEqual(lit.Value, test.value) {
+				t.Fatalf("expected the value %#v, got %#v", test.value, lit.Value)
+			}
+			if lit.Raw != test.literal {
+				t.Fatalf("expected the raw text %s, got %s", test.literal, lit.Raw)
+			}
+		})
+	}
+}
+
+func TestLiteralTypes(t *testing.T) {
+	parsedStmt := mustParse(t, newTestParser(), "SELECT 'a' AS s, 1 AS i, 1.5 AS d, 1e3 AS f, FALSE AS b, DATE '2024-01-01' AS day FROM users")
+	var types []DataType
+	for _, column := range parsedStmt.Result {
+		types = append(types, column.Type)
+	}
+	if want := []DataType{TextType, IntegerType, DecimalType, FloatType, BooleanType, DateType}; !reflect.DeepEqual(types, want) {
+		t.Fatalf("expected the result types %v, got %v", want, types)
+	}
+}
+
+func mustRat(s string) *big.Rat {
+	value, _ := new(big.Rat).SetString(s)
+	return value
+}
@@ -0,0 +1,91 @@
+package sqlParser
+
+import (
+	"sort"
+	"strings"
+)
+
+// Identifier is a single table, column or index name. Unquoted identifiers
+// are folded to upper case and match the schema ignoring case, quoted
+// identifiers keep their case and must match exactly.
+type Identifier struct {
+	Name   string
+	Quoted bool
+}
+
+func (id Identifier) String() string {
+	if id.Quoted {
+		return `"` + strings.ReplaceAll(id.Name, `"`, `""`) + `"`
+	}
+	return id.Name
+}
+
+// IsEmpty reports whether the identifier was left out, as is the schema of
+// an unqualified table name.
+func (id Identifier) IsEmpty() bool {
+	return id.Name == ""
+}
+
+// Matches reports whether name, as stored in the schema, is designated by
+// the identifier.
+func (id Identifier) Matches(name string) bool {
+	if id.Quoted {
+		return id.Name == name
+	}
+	return strings.EqualFold(id.Name, name)
+}
+
+// resolve returns the name designated by the identifier among names. An
+// unquoted identifier prefers the name spelled exactly like it, then the
+// first one equal ignoring case.
+func (id Identifier) resolve(names []string) (string, bool) {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		if name == id.Name {
+			return name, true
+		}
+	}
+	if id.Quoted {
+		return "", false
+	}
+	for _, name := range sorted {
+		if id.Matches(name) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// TableName is a table name, optionally qualified by its schema: schema.table
+type TableName struct {
+	Trivia
+	Schema Identifier
+	Name   Identifier
+}
+
+func (table TableName) String() string {
+	if table.Schema.IsEmpty() {
+		return table.Name.String()
+	}
+	return table.Schema.String() + "." + table.Name.String()
+}
+
+// identifierNames returns the names of the identifiers.
+func identifierNames(ids []Identifier) []string {
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		names = append(names, id.Name)
+	}
+	return names
+}
+
+// joinIdentifiers returns the dotted SQL text of the identifiers.
+func joinIdentifiers(ids []Identifier) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, id.String())
+	}
+	return strings.Join(parts, ".")
+}
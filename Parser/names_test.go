@@ -0,0 +1,57 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQuotedAndQualifiedNames(t *testing.T) {
+	runParseTests(t, newTestParser, []parseTest{
+		{name: "unquoted names ignore case", sql: "select Id from Users"},
+		{name: "quoted names", sql: `SELECT "ID" FROM "USERS"`},
+		{name: "quoted name is case sensitive", sql: `SELECT "id" FROM users`, err: `column "id" does not exist in table USERS`},
+		{name: "quoted table is case sensitive", sql: `SELECT id FROM "users"`, err: `table "users" does not exist in the schema`},
+		{name: "qualified column", sql: "SELECT users.id FROM users"},
+		{name: "schema qualified names", sql: "SELECT public.users.id FROM public.users"},
+		{name: "unknown schema", sql: "SELECT id FROM sales.users", err: "schema SALES does not exist"},
+		{name: "table not in the statement", sql: "SELECT orders.id FROM users", err: "table ORDERS of column ORDERS.ID is not part of the statement"},
+		{name: "unknown column", sql: "SELECT nope FROM users", err: "column NOPE does not exist in table USERS"},
+		{name: "unknown column of several tables", sql: "DELETE FROM users USING orders WHERE nope = 1", err: "column NOPE does not exist in tables USERS, ORDERS"},
+		{name: "ambiguous column", sql: "DELETE FROM users USING orders WHERE id = 1", err: "column ID is ambiguous, it exists in tables USERS and ORDERS"},
+		{name: "qualified column of several tables", sql: "DELETE FROM users USING orders WHERE users.id = orders.user_id"},
+		{name: "empty quoted identifier", sql: `SELECT "" FROM users`, err: "zero-length quoted identifier at position 7"},
+		{name: "unterminated quoted identifier", sql: `SELECT "id FROM users`, err: "unterminated quoted identifier at position 7"},
+	})
+}
+
+func TestIdentifierQuotes(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		sql     string
+		err     string
+	}{
+		{dialect: MySQLDialect, sql: "SELECT `ID` FROM `USERS`"},
+		{dialect: SQLServerDialect, sql: "SELECT [ID] FROM [USERS]"},
+		{dialect: SQLiteDialect, sql: "SELECT \"ID\", `NAME`, [AGE] FROM users"},
+		{dialect: MySQLDialect, sql: `SELECT "ID" FROM users`, err: `unexpected character '"'`},
+		{dialect: PostgresDialect, sql: "SELECT `ID` FROM users", err: "unexpected character '`'"},
+	}
+	for _, test := range tests {
+		t.Run(test.dialect.Name, func(t *testing.T) {
+			parser := newTestParser()
+			parser.Dialect = test.dialect
+			_, err := parser.ParseSQL(test.sql)
+			checkError(t, err, test.err)
+		})
+	}
+}
+
+func TestResolvedNames(t *testing.T) {
+	parsedStmt := mustParse(t, newTestParser(), `SELECT Users.name, "AGE" FROM users WHERE Id = 1`)
+	if want := []string{"USERS"}; !reflect.DeepEqual(parsedStmt.Tables, want) {
+		t.Fatalf("expected the tables %v, got %v", want, parsedStmt.Tables)
+	}
+	if want := []string{"NAME", "AGE"}; !reflect.DeepEqual(parsedStmt.Columns, want) {
+		t.Fatalf("expected the columns %v, got %v", want, parsedStmt.Columns)
+	}
+}
@@ -0,0 +1,77 @@
+package sqlParser
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ParamInfo describes a bind parameter of an analyzed statement, so that the
+// values bound by a client can be checked before execution.
+//
+// Type is inferred from the column the parameter is compared to or inserted
+// into. It is UnknownType when the parameter is not used against a column or
+// when the schema does not record the type of the column.
+type ParamInfo struct {
+	Raw    string // the parameter as first written: ?, $1, :name or @name
+	Index  int    // position of a ? parameter or number of a $n one, 0 for named ones
+	Name   string // name of a :name or @name parameter
+	Type   DataType
+	Table  string // table and column the type was inferred from
+	Column string
+}
+
+// paramSet gathers the parameters of a statement. A parameter used several
+// times, such as $1 or :name, is listed once.
+type paramSet struct {
+	params []ParamInfo
+}
+
+// add records a use of the parameter against the given column, table and
+// column are empty when the parameter is not used against a column.
+func (set *paramSet) add(param *Param, table, column string, typ DataType) error {
+	for i := range set.params {
+		info := &set.params[i]
+		if info.Index != param.Index || info.Name != param.Name {
+			continue
+		}
+		if info.Type == UnknownType {
+			info.Type, info.Table, info.Column = typ, table, column
+			return nil
+		}
+		common, ok := commonType(info.Type, typ)
+		if !ok {
+			return fmt.Errorf("parameter %s is used both as %s and %s", param.Raw, info.Type, typ)
+		}
+		info.Type = common
+		return nil
+	}
+
+	set.params = append(set.params, ParamInfo{
+		Raw:    param.Raw,
+		Index:  param.Index,
+		Name:   param.Name,
+		Type:   typ,
+		Table:  table,
+		Column: column,
+	})
+	return nil
+}
+
+// addInfo records a parameter gathered by the analysis of a sub statement.
+func (set *paramSet) addInfo(info ParamInfo) error {
+	param := &Param{Raw: info.Raw, Index: info.Index, Name: info.Name}
+	return set.add(param, info.Table, info.Column, info.Type)
+}
+
+// list returns the parameters: the positional and numbered ones ordered by
+// number, followed by the named ones in order of appearance.
+func (set *paramSet) list() []ParamInfo {
+	params := set.params
+	sort.SliceStable(params, func(i, j int) bool {
+		if params[i].Index == 0 || params[j].Index == 0 {
+			return params[i].Index != 0 && params[j].Index == 0
+		}
+		return params[i].Index < params[j].Index
+	})
+	return params
+}
@@ -0,0 +1,65 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		sql    string
+		params []ParamInfo
+		err    string
+	}{
+		{
+			name: "positional parameters",
+			sql:  "SELECT id FROM users WHERE name = ? AND age > ?",
+			params: []ParamInfo{
+				{Raw: "?", Index: 1, Type: TextType, Table: "USERS", Column: "NAME"},
+				{Raw: "?", Index: 2, Type: IntegerType, Table: "USERS", Column: "AGE"},
+			},
+		},
+		{
+			name: "numbered parameters used twice",
+			sql:  "SELECT id FROM users WHERE age > $2 AND id <> $1 AND age < $2",
+			params: []ParamInfo{
+				{Raw: "$1", Index: 1, Type: IntegerType, Table: "USERS", Column: "ID"},
+				{Raw: "$2", Index: 2, Type: IntegerType, Table: "USERS", Column: "AGE"},
+			},
+		},
+		{
+			name: "named parameters",
+			sql:  "UPDATE users SET email = :email WHERE id = @id",
+			params: []ParamInfo{
+				{Raw: ":email", Name: "email", Type: TextType, Table: "USERS", Column: "EMAIL"},
+				{Raw: "@id", Name: "id", Type: IntegerType, Table: "USERS", Column: "ID"},
+			},
+		},
+		{
+			name: "inserted values",
+			sql:  "INSERT INTO orders (id, total) VALUES (?, ?)",
+			params: []ParamInfo{
+				{Raw: "?", Index: 1, Type: IntegerType, Table: "ORDERS", Column: "ID"},
+				{Raw: "?", Index: 2, Type: DecimalType, Table: "ORDERS", Column: "TOTAL"},
+			},
+		},
+		{
+			name:   "parameter not used against a column",
+			sql:    "SELECT id FROM users WHERE ? = ?",
+			params: []ParamInfo{{Raw: "?", Index: 1, Type: UnknownType}, {Raw: "?", Index: 2, Type: UnknownType}},
+		},
+		{name: "mixed parameters", sql: "SELECT id FROM users WHERE id = ? AND age = $1", err: "? and $n parameters can not be mixed"},
+		{name: "parameter zero", sql: "SELECT id FROM users WHERE id = $0", err: "invalid parameter $0"},
+		{name: "conflicting types", sql: "SELECT id FROM users WHERE id = $1 AND name = $1", err: "parameter $1 is used both as INTEGER and TEXT"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsedStmt, err := newTestParser().ParseSQL(test.sql)
+			checkError(t, err, test.err)
+			if test.err == "" && !reflect.DeepEqual(parsedStmt.Params, test.params) {
+				t.Fatalf("expected the parameters %+v, got %+v", test.params, parsedStmt.Params)
+			}
+		})
+	}
+}
@@ -8,27 +8,50 @@ import (
 
 // some struct and interface definitions can be separated into another file later on
 type Token struct {
-	Value string
-	Type  TokenType
+	Value    string
+	Type     TokenType
+	Pos      int       // byte offset of the token in the statement
+	Comments []Comment // comments met before the token, only kept when SQLParser.KeepComments is set
 }
 
 type ParsedStmt struct {
-	QueryType  QueryType     // Type of the query (e.g., SELECT, INSERT, UPDATE, DELETE)
-	Tables     []string      // Tables involved in the statement
-	Columns    []string      // Columns referenced in the statement
-	Values     []string      // Values specified in the statement
-	Conditions []interface{} // Conditions and operators specified in the statement
+	QueryType  QueryType      // Type of the query (e.g., SELECT, INSERT, UPDATE, DELETE)
+	Tables     []string       // Tables involved in the statement
+	Columns    []string       // Columns referenced in the statement
+	Values     []string       // Values specified in the statement
+	Conditions []interface{}  // Conditions and operators specified in the statement
+	Stmt       Statement      // AST of the statement
+	Params     []ParamInfo    // Bind parameters of the statement, filled by the semantic analysis
+	Result     []ResultColumn // Columns of the result set, filled by the semantic analysis
 }
 
 // SQLParser represents an SQL parser instance.
 type SQLParser struct {
-	Schema Schema
+	Schema       Schema
+	Dialect      Dialect // SQL flavour accepted by the parser, GenericDialect by default
+	KeepComments bool    // attach the comments to the AST nodes instead of dropping them
+	SafeMode     bool    // reject the destructive writes, see IsDestructiveWrite
+	// AllowFullTableWrites accepts the destructive writes in safe mode, it
+	// is set around the statements meant to change every row of a table.
+	AllowFullTableWrites bool
+	// Functions lists the functions that can be called in expressions, the
+	// built-in ones when nil.
+	Functions *FunctionRegistry
+	// Prepared holds the statements prepared by PREPARE during the session.
+	Prepared PreparedRegistry
+	// Privileges is the privilege model the statements of the current user
+	// are checked against, no checks are made when nil.
+	Privileges *Privileges
+	// CheckTransactions makes ParseScript check the transaction control
+	// statements of the script with a TransactionChecker.
+	CheckTransactions bool
 }
 
-// NewSQLParser creates a new SQLParser instance.
+// NewSQLParser creates a new SQLParser instance. The parser works on a copy
+// of the schema: the statements changing the catalog leave the caller's
+// schema as it is.
 func NewSQLParser(schema Schema) *SQLParser {
-	schema.LoadSchema("schema.db")
-	return &SQLParser{Schema: schema}
+	return &SQLParser{Schema: schema.clone(), Dialect: GenericDialect, Functions: NewFunctionRegistry()}
 }
 
 // ParseSQL parses the given SQL statement and returns the parsed representation.
@@ -39,13 +62,11 @@ func (parser *SQLParser) ParseSQL(sql string) (parsedStmt ParsedStmt, err error)
 		return ParsedStmt{}, err
 	}
 
-	tokenError, err := parser.validateSyntax(tokens)
+	_, err = parser.validateSyntax(tokens)
 	if err != nil {
 		return ParsedStmt{}, err
 	}
 
-	fmt.Println("Tokens: ", tokenError)
-
 	parsedStmtPtr, err := parser.parse(tokens)
 	if err != nil {
 		return ParsedStmt{}, err
@@ -56,485 +77,776 @@ func (parser *SQLParser) ParseSQL(sql string) (parsedStmt ParsedStmt, err error)
 		return ParsedStmt{}, err
 	}
 
+	if parser.SafeMode && !parser.AllowFullTableWrites {
+		err = checkSafeWrite(parsedStmt.Stmt)
+		if err != nil {
+			return ParsedStmt{}, err
+		}
+	}
+
+	parser.updateCatalog(parsedStmt)
 	return parsedStmt, nil
 }
 
-func (parser *SQLParser) Tokenize(sql string) ([]Token, error) {
-	sql = strings.ToUpper(sql)
-	fmt.Println("sql : ", sql)
-	var tokens []Token
+// updateCatalog applies the statements changing the catalog to the schema,
+// USE and SET search_path to the current schema, PREPARE and DEALLOCATE to
+// the prepared statements of the session and GRANT and REVOKE to the
+// privilege model, later statements see their effect. The statements
+// explained by EXPLAIN are not run and leave the schema as is.
+func (parser *SQLParser) updateCatalog(parsedStmt ParsedStmt) {
+	switch stmt := parsedStmt.Stmt.(type) {
+	case *PrepareStmt:
+		prepared := parsedStmt
+		prepared.QueryType = stmt.Stmt.QueryType()
+		prepared.Stmt = stmt.Stmt
+		parser.Prepared.put(PreparedStatement{Name: stmt.Name.Name, Stmt: prepared, Params: parsedStmt.Params})
+	case *DeallocateStmt:
+		if stmt.All {
+			parser.Prepared.clear()
+		} else {
+			parser.Prepared.remove(stmt.Name.Name)
+		}
+	case *CreateView:
+		// the user creating a view owns it
+		if parser.Privileges != nil && !parser.Schema.IsView(parsedStmt.Tables[0]) {
+			parser.Privileges.setOwner(parsedStmt.Tables[0], parser.Privileges.User)
+		}
+		parser.Schema.putView(View{Name: parsedStmt.Tables[0], Columns: parsedStmt.Result, Query: stmt.Select, Tables: parsedStmt.Tables[1:]})
+	case *Create:
+		if !stmt.Index.IsEmpty() {
+			parser.Schema.putIndex(parsedStmt.Tables[0], Index{Name: stmt.Index.Name, Columns: parsedStmt.Columns})
+			break
+		}
+		// the user creating a table owns it
+		if parser.Privileges != nil {
+			parser.Privileges.setOwner(parsedStmt.Tables[0], parser.Privileges.User)
+		}
+		parser.Schema.putTable(parsedStmt.Tables[0], parsedStmt.Columns)
+	case *Drop:
+		switch {
+		case !stmt.Index.IsEmpty():
+			_, index, _ := parser.Schema.lookupIndex(stmt.Index)
+			parser.Schema.dropIndex(parsedStmt.Tables[0], index)
+		case !stmt.View.Name.IsEmpty():
+			parser.Schema.dropView(parsedStmt.Tables[0])
+		default:
+			parser.Schema.dropTable(parsedStmt.Tables[0])
+		}
+		if stmt.Index.IsEmpty() && parser.Privileges != nil {
+			parser.Privileges.dropTable(parsedStmt.Tables[0])
+		}
+	case *GrantStmt:
+		if parser.Privileges != nil {
+			parser.applyGrant(stmt, parsedStmt.Tables)
+		}
+	case *CreateSchema:
+		if _, err := parser.Schema.LookupSchema(stmt.Name); err != nil {
+			parser.Schema.createSchema(stmt.Name.Name)
+		}
+	case *DropSchema:
+		if name, err := parser.Schema.LookupSchema(stmt.Name); err == nil {
+			parser.Schema.dropSchema(name)
+		}
+		if parser.Privileges != nil {
+			for _, table := range parsedStmt.Tables {
+				parser.Privileges.dropTable(table)
+			}
+		}
+	case *UseStmt:
+		parser.Schema.Current, _ = parser.Schema.LookupSchema(stmt.Schema)
+	case *SetStmt:
+		path, _ := parser.validateSet(stmt)
+		parser.Schema.setSearchPath(path)
+	}
+}
 
-	// get the first token from the input string reading from start to the first space
-	var firstStatement string = strings.Split(sql, " ")[0]
+// IsDestructiveWrite reports whether the statement deletes or changes every
+// row of a table: a TRUNCATE, or an UPDATE or DELETE without a LIMIT and
+// without a WHERE clause reading a column, WHERE TRUE or WHERE 1 = 1 keeping
+// every row. Safety policies, as SQLParser.SafeMode, reject such statements.
+func IsDestructiveWrite(stmt Statement) bool {
+	switch stmt := stmt.(type) {
+	case *UpdateStmt:
+		return !readsColumn(stmt.Where) && stmt.Limit == nil
+	case *DeleteStmt:
+		return !readsColumn(stmt.Where) && stmt.Limit == nil
+	case *TruncateStmt:
+		return true
+	case *ExplainStmt:
+		// EXPLAIN ANALYZE runs the statement
+		return stmt.Analyze && IsDestructiveWrite(stmt.Stmt)
+	case *PrepareStmt:
+		// the prepared statement runs on every EXECUTE
+		return IsDestructiveWrite(stmt.Stmt)
+	}
+	return false
+}
 
-	queryType, err := paresQueryType(firstStatement)
+// readsColumn reports whether the expression reads a column, nil reading
+// none.
+func readsColumn(expr Expr) bool {
+	found := false
+	walkExpr(expr, func(expr Expr) bool {
+		if _, ok := expr.(*ColumnRef); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
 
-	if err != nil {
-		return nil, errors.New("invalid query type")
+// checkSafeWrite rejects the destructive writes, which are only accepted
+// with SQLParser.AllowFullTableWrites.
+func checkSafeWrite(stmt Statement) error {
+	if !IsDestructiveWrite(stmt) {
+		return nil
 	}
+	switch stmt := stmt.(type) {
+	case *UpdateStmt:
+		return fmt.Errorf("safe mode: UPDATE without a WHERE clause reading a column changes every row of table %s", stmt.Table)
+	case *DeleteStmt:
+		return fmt.Errorf("safe mode: DELETE without a WHERE clause reading a column deletes every row of table %s", stmt.Table)
+	case *TruncateStmt:
+		return fmt.Errorf("safe mode: TRUNCATE deletes every row of table %s", stmt.Tables[0])
+	case *ExplainStmt:
+		return checkSafeWrite(stmt.Stmt)
+	case *PrepareStmt:
+		return checkSafeWrite(stmt.Stmt)
+	}
+	return nil
+}
 
-	//add the first token to the tokens list
-	tokens = append(tokens, Token{Type: "keyword", Value: firstStatement})
-	//remove the first word from the string
-	sql = strings.Replace(sql, firstStatement, "", 1)
-
-	switch queryType {
-	case SelectQuery: 
-		// SELECT col1, col2, ... FROM table_name WHERE condition;
-		//get the collection of column names
-		//get the substring that is between the first space and the first "FROM" keyword
-		var colsString string = strings.Split(sql, "FROM")[0]
-		//remove the spaces from the string
-		colsString = strings.Replace(colsString, " ", "", -1)
-		//split the string by the comma
-		columns := strings.Split(colsString, ",")
-		//add the columns to the tokens list
-		for _, col := range columns {
-			tokens = append(tokens, Token{Type: "column", Value: col})
-		}
-		//get the substring after the first "FROM" keyword
-		var tablesString string = strings.Split(sql, "FROM")[1]
-
-		whereKeyword := strings.Split(tablesString, "WHERE")
-		if len(whereKeyword) > 1 {
-			//split at where keyword
-			tablesString = strings.Split(tablesString, "WHERE")[0]
-			tablesString = strings.Trim(tablesString, " ")
-			if len(strings.Split(tablesString, " ")) > 1 {
-				return nil, errors.New("invalid table name in SELECT statement")
-			}
-			
-			if len(tablesString) == 0 {
-				return nil, errors.New("missing table name in SELECT statement")
-			}
-			//add the table to the tokens list
-			tokens = append(tokens, Token{Type: "table", Value: tablesString})
-			//parse the conditions
-			tokens, err = parseCondition(tokens, sql)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			tablesString = strings.Trim(tablesString, " ")
-			if len(strings.Split(tablesString, " ")) > 1 {
-				return nil, errors.New("invalid table name in SELECT statement")
-			}
-			if len(tablesString) == 0 {
-				return nil, errors.New("missing table name in SELECT statement")
+// Tokenize breaks the SQL string down into keywords, identifiers, operators
+// and constants. The last token is always an EOFToken.
+func (parser *SQLParser) Tokenize(sql string) ([]Token, error) {
+	return newLexer(sql, parser.dialect(), parser.KeepComments).lex()
+}
+
+// validateSyntax checks the token stream for errors that can be found
+// without parsing it, such as unmatched parentheses. It returns the
+// offending tokens along with the error.
+func (parser *SQLParser) validateSyntax(tokens []Token) ([]Token, error) {
+	var open []Token
+	for _, token := range tokens {
+		if token.Type != PunctuationToken {
+			continue
+		}
+		switch token.Value {
+		case "(":
+			open = append(open, token)
+		case ")":
+			if len(open) == 0 {
+				return []Token{token}, fmt.Errorf("syntax error: unmatched ')' at position %d", token.Pos)
 			}
-			tablesString = strings.Replace(tablesString, ";", "", -1)
-			//add the table to the tokens list
-			tokens = append(tokens, Token{Type: "table", Value: tablesString})
+			open = open[:len(open)-1]
 		}
+	}
+	if len(open) > 0 {
+		return open, fmt.Errorf("syntax error: unmatched '(' at position %d", open[len(open)-1].Pos)
+	}
+	return nil, nil
+}
 
-	case DeleteQuery:
-		// DELETE FROM table_name WHERE condition;
-		var tablesString string = strings.Split(sql, "FROM")[1]
-		if len(tablesString) == 0 {
-			return nil, errors.New("missing table name in DELETE statement")
+// parse validates the token order and structure according to the chosen grammar.
+func (parser *SQLParser) parse(tokens []Token) (parsedStmt *ParsedStmt, err error) {
+	stmt, err := newStmtParser(tokens, parser.dialect()).parseStatement()
+	if err != nil {
+		return nil, err
+	}
+
+	return flattenStatement(stmt), nil
+}
+
+// flattenStatement fills the fields of the ParsedStmt listing the tables,
+// columns, values and conditions of the statement as they were written.
+func flattenStatement(stmt Statement) *ParsedStmt {
+	parsedStmt := &ParsedStmt{QueryType: stmt.QueryType(), Stmt: stmt}
+	switch node := stmt.(type) {
+	case *SelectStmt:
+		for _, table := range node.Tables {
+			parsedStmt.Tables = append(parsedStmt.Tables, table.Name.Name)
+		}
+		for _, item := range node.Columns {
+			column, ok := item.Expr.(*ColumnRef)
+			switch {
+			case !item.Alias.IsEmpty():
+				parsedStmt.Columns = append(parsedStmt.Columns, item.Alias.Name)
+			case ok:
+				parsedStmt.Columns = append(parsedStmt.Columns, column.Column.Name)
+			default:
+				parsedStmt.Columns = append(parsedStmt.Columns, item.Expr.String())
+			}
 		}
-		tablesString = strings.Split(tablesString, "WHERE")[0]
-		tablesString = strings.Trim(tablesString, " ")
-		if len(strings.Split(tablesString, " ")) > 1 {
-			return nil, errors.New("invalid table name in DELETE statement")
+		parsedStmt.Conditions = flattenConditions(node.Where)
+	case *InsertStmt:
+		parsedStmt.Tables = []string{node.Table.Name.Name}
+		parsedStmt.Columns = identifierNames(node.Columns)
+		for _, row := range node.Rows {
+			parsedStmt.Values = append(parsedStmt.Values, exprStrings(row)...)
 		}
-
-		if len(tablesString) == 0 {
-			return nil, errors.New("missing table name in DELETE statement")
+	case *UpdateStmt:
+		for _, table := range node.tables() {
+			parsedStmt.Tables = append(parsedStmt.Tables, table.Name.Name)
 		}
-		tokens = append(tokens, Token{Type: "table", Value: tablesString})
+		for _, assignment := range node.Set {
+			for _, column := range assignment.Columns {
+				parsedStmt.Columns = append(parsedStmt.Columns, column.Column.Name)
+			}
+			parsedStmt.Values = append(parsedStmt.Values, exprStrings(assignment.Values)...)
+		}
+		parsedStmt.Conditions = flattenConditions(node.Where)
+	case *DeleteStmt:
+		for _, table := range node.tables() {
+			parsedStmt.Tables = append(parsedStmt.Tables, table.Name.Name)
+		}
+		parsedStmt.Conditions = flattenConditions(node.Where)
+	case *Drop:
+		if !node.Table.Name.IsEmpty() {
+			parsedStmt.Tables = []string{node.Table.Name.Name}
+		}
+		if !node.View.Name.IsEmpty() {
+			parsedStmt.Tables = []string{node.View.Name.Name}
+		}
+	case *Create:
+		parsedStmt.Tables = []string{node.Table.Name.Name}
+		parsedStmt.Columns = identifierNames(node.Columns)
+	case *ShowStmt:
+		if !node.Table.Name.IsEmpty() {
+			parsedStmt.Tables = []string{node.Table.Name.Name}
+		}
+	case *TruncateStmt:
+		for _, table := range node.Tables {
+			parsedStmt.Tables = append(parsedStmt.Tables, table.Name.Name)
+		}
+	case *CreateView:
+		parsedStmt.Tables = []string{node.Name.Name.Name}
+		parsedStmt.Columns = identifierNames(node.Columns)
+	case *ExecuteStmt:
+		parsedStmt.Tables = []string{node.Name.Name}
+		parsedStmt.Values = exprStrings(node.Args)
+	case *DeallocateStmt:
+		if !node.All {
+			parsedStmt.Tables = []string{node.Name.Name}
+		}
+	case *GrantStmt:
+		for _, table := range node.Tables {
+			parsedStmt.Tables = append(parsedStmt.Tables, table.Name.Name)
+		}
+	case *ExplainStmt:
+		// the explained statement gives the tables, columns and values
+		*parsedStmt = *flattenStatement(node.Stmt)
+		parsedStmt.QueryType = ExplainQuery
+		parsedStmt.Stmt = node
+	case *PrepareStmt:
+		*parsedStmt = *flattenStatement(node.Stmt)
+		parsedStmt.QueryType = PrepareQuery
+		parsedStmt.Stmt = node
+	}
+	return parsedStmt
+}
 
-		tokens, err = parseCondition(tokens, sql)
-		if err != nil {
-			return nil, err
+// semanticAnalysis interprets the parsed structure and assigns meaning based on the schema.
+// The names of the tables and columns are resolved against the schema and
+// reported as stored there. With a privilege model, the statements the
+// current user may not run are rejected.
+func (parser *SQLParser) semanticAnalysis(parsedStmt ParsedStmt) (ParsedStmt, error) {
+	reads := columnReads{}
+	parsedStmt, err := parser.analyzeStatement(parsedStmt, reads)
+	if err != nil {
+		return ParsedStmt{}, err
+	}
+	if parser.Privileges != nil {
+		if err := parser.checkPrivileges(parsedStmt, reads); err != nil {
+			return ParsedStmt{}, err
 		}
+	}
+	return parsedStmt, nil
+}
 
-	case UpdateQuery:
-		// UPDATE table_name SET col1 = value1, col2 = value2, ... WHERE condition;
-		var tablesString string = strings.Split(sql, "SET")[0]
+// analyzeStatement resolves and checks the statement, gathering the columns
+// it reads.
+func (parser *SQLParser) analyzeStatement(parsedStmt ParsedStmt, reads columnReads) (res ParsedStmt, err error) {
 
-		tablesString = strings.Trim(tablesString, " ")
-		if len(strings.Split(tablesString, " ")) > 1 {
-			return nil, errors.New("invalid table name in UPDATE statement")
-		}
+	var params paramSet
 
-		if len(tablesString) == 0 {
-			return nil, errors.New("missing table name in UPDATE statement")
+	switch stmt := parsedStmt.Stmt.(type) {
+	case *SelectStmt:
+		// check if the tables exist in the schema
+		tables, err := parser.validateTableExistence(stmt.Tables)
+		if err != nil {
+			return ParsedStmt{}, err
 		}
-		tokens = append(tokens, Token{Type: "table", Value: tablesString})
-
-		var colsString string = strings.Split(sql, "SET")[1]
-		colsString = strings.Split(colsString, "WHERE")[0]
-
-		if len(colsString) == 0 {
-			return nil, errors.New("missing columns in UPDATE statement")
+		// the select list may use aggregates and window functions, the WHERE
+		// and GROUP BY clauses may not
+		scope := exprScope{tables: tables, params: &params, reads: reads}
+		listScope := exprScope{tables: tables, aggregates: true, windows: true, namedWindows: stmt.Windows, params: &params, reads: reads}
+		err = parser.validateWindows(stmt.Windows, listScope)
+		if err != nil {
+			return ParsedStmt{}, err
 		}
-		colsString = strings.Replace(colsString, " ", "", -1)
-		columns := strings.Split(colsString, ",")
-
-		for _, col := range columns {
-				operator := strings.Split(col, "=")
-				if len(operator) != 2 {
-					return nil, errors.New("invalid column value pair in UPDATE statement")
-				}
-			val := strings.Split(col, "=")[1]
-			col = strings.Split(col, "=")[0]
-			tokens = append(tokens, Token{Type: "column", Value: col})
-			tokens = append(tokens, Token{Type: "value", Value: val})
+		result, err := parser.resultColumns(stmt.Columns, listScope)
+		if err != nil {
+			return ParsedStmt{}, err
 		}
-
-		tokens, err = parseCondition(tokens, sql)
+		err = parser.validateConditionExistence(stmt.Where, scope)
 		if err != nil {
-			return nil, err
+			return ParsedStmt{}, err
 		}
-
-	case InsertQuery:
-		// INSERT INTO table_name (col1, col2, ...) VALUES (value1, value2, ...);
-		parenthesis := strings.Split(sql, "(")
-		if len(parenthesis) < 3 {
-			return nil, errors.New("missing parenthesis in INSERT statement")
+		groupBy, err := parser.validateGroupBy(stmt, scope)
+		if err != nil {
+			return ParsedStmt{}, err
 		}
-		var tablesString string = strings.Split(sql, "INTO")[1]
-		tablesString = strings.Split(tablesString, "(")[0]
-		tablesString = strings.Trim(tablesString, " ")
-		if len(strings.Split(tablesString, " ")) > 1 {
-			return nil, errors.New("invalid table name in INSERT statement")
+		err = parser.validateSelectOrder(stmt, listScope)
+		if err != nil {
+			return ParsedStmt{}, err
 		}
-
-		tablesString = strings.Replace(tablesString, " ", "", -1)
-		if len(tablesString) == 0 {
-			return nil, errors.New("missing table name in INSERT statement")
+		err = parser.validateGrouping(stmt, groupBy, listScope)
+		if err != nil {
+			return ParsedStmt{}, err
 		}
-		tokens = append(tokens, Token{Type: "table", Value: tablesString})
-
-		parenthesis = strings.Split(sql, ")")
-		if len(parenthesis) < 3 {
-			return nil, errors.New("missing parenthesis in INSERT statement")
+		if err := parser.checkLimit("LIMIT", stmt.Limit, &params); err != nil {
+			return ParsedStmt{}, err
 		}
-
-		var colsString string = strings.Split(sql, "(")[1]
-		colsString = strings.Split(colsString, ")")[0]
-		colsString = strings.Replace(colsString, " ", "", -1)
-		columns := strings.Split(colsString, ",")
-		if len(columns) == 0 {
-			return nil, errors.New("missing columns in INSERT statement")
+		if err := parser.checkLimit("OFFSET", stmt.Offset, &params); err != nil {
+			return ParsedStmt{}, err
 		}
-
-		var valuesString string = strings.Split(sql, "VALUES")[1]
-		valuesString = strings.Split(valuesString, "(")[1]
-		valuesString = strings.Split(valuesString, ")")[0]
-		valuesString = strings.Replace(valuesString, " ", "", -1)
-		values := strings.Split(valuesString, ",")
-
-		if len(columns) != len(values) {
-			return nil, errors.New("invalid number of columns and values")
+		parsedStmt.Tables = tables
+		parsedStmt.Columns = nil
+		for _, column := range result {
+			parsedStmt.Columns = append(parsedStmt.Columns, column.Name)
 		}
+		parsedStmt.Result = result
+		parsedStmt.Params = params.list()
+		return parsedStmt, nil
 
-		for i := range columns {
-			tokens = append(tokens, Token{Type: "column", Value: columns[i]})
-			tokens = append(tokens, Token{Type: "value", Value: values[i]})
+	case *InsertStmt:
+		tables, err := parser.validateTableExistence([]TableName{stmt.Table})
+		if err != nil {
+			return ParsedStmt{}, err
 		}
-
-	case DropQuery:
-		words := strings.Fields(sql)
-		if len(words) > 2 {
-			return nil, errors.New("invalid DROP statement")
+		// check if the columns exist in the table, without a column list
+		// the columns of the table are filled in their schema order
+		columns := parser.Schema.GetTableColumns(tables[0])
+		if len(stmt.Columns) > 0 {
+			columns, err = parser.validateColumnExistence(tables, columnRefs(stmt.Columns))
+			if err != nil {
+				return ParsedStmt{}, err
+			}
+			if valid, column := containsNoDuplicates(columns); !valid {
+				return ParsedStmt{}, fmt.Errorf("column %s is specified more than once", column)
+			}
 		}
-		dropType := words[0]
-		switch dropType {
-		case "TABLE":
-			tableName := words[1]
-			tokens = append(tokens, Token{Type: "table", Value: refineFieldName(tableName)})
-		case "INDEX":
-			indexName := words[1]
-			tokens = append(tokens, Token{Type: "index", Value: refineFieldName(indexName)})
-		default:
-			return nil, errors.New("invalid DROP statement")
+		// a view is written through to the one table it reads
+		if err := parser.checkUpdatableView(tables[0]); err != nil {
+			return ParsedStmt{}, err
 		}
-
-	case CreateQuery:
-		words := strings.Fields(sql)
-		CreateType := words[0]
-		switch CreateType {
-		case "TABLE":
-			tableName := words[1]
-			tokens = append(tokens, Token{Type: "table", Value: refineFieldName(tableName)})
-
-			var colsString string = strings.Split(sql, "(")[1]
-			colsString = strings.Split(colsString, ")")[0]
-			colsString = strings.Replace(colsString, " ", "", -1)
-			columns := strings.Split(colsString, ",")
-
-			for _, col := range columns {
-				tokens = append(tokens, Token{Type: "column", Value: refineFieldName(col)})
+		for _, column := range columns {
+			if err := parser.checkUpdatableColumn(tables[0], column); err != nil {
+				return ParsedStmt{}, err
 			}
-
-		case "INDEX":
-			indexName := words[1]
-			tokens = append(tokens, Token{Type: "index", Value: refineFieldName(indexName)})
-
-			var tableName string = strings.Split(sql, "ON")[1]
-			tableName = strings.Split(tableName, "(")[0]
-			tokens = append(tokens, Token{Type: "table", Value: refineFieldName(tableName)})
-
-			var colsString string = strings.Split(sql, "(")[1]
-			colsString = strings.Split(colsString, ")")[0]
-			colsString = strings.Replace(colsString, " ", "", -1)
-			columns := strings.Split(colsString, ",")
-
-			for _, col := range columns {
-				tokens = append(tokens, Token{Type: "column", Value: refineFieldName(col)})
+		}
+		// check if every row matches the columns in count and type
+		err = parser.validateInsertRows(stmt, tables[0], columns, &params)
+		if err != nil {
+			return ParsedStmt{}, err
+		}
+		if stmt.OnConflict != nil {
+			err = parser.validateOnConflict(stmt, tables[0], &params, reads)
+			if err != nil {
+				return ParsedStmt{}, err
 			}
-		default:
-			return nil, errors.New("invalid CREATE statement :(")
-
 		}
+		parsedStmt.Tables = tables
+		parsedStmt.Columns = columns
+		parsedStmt.Result, err = parser.resultColumns(stmt.Returning, exprScope{tables: tables, params: &params, reads: reads})
+		if err != nil {
+			return ParsedStmt{}, err
+		}
+		parsedStmt.Params = params.list()
+		return parsedStmt, nil
 
-	default:
-		return nil, errors.New("unsupported query type yet :(")
-	}
-
-	return tokens, nil
-}
-
-func (parser *SQLParser) validateSyntax(tokens []Token) ([]Token, error) {
-	// Implement syntax checking logic, such as checking for unmatched parentheses, missing semicolons, etc.
-	// ...
-
-	// Example of syntax error detection:
-	for _, token := range tokens {
-		if token.Type == "invalid" {
-			return []Token{{Value: token.Value}}, errors.New("syntax error: Invalid token found")
+	case *UpdateStmt:
+		tables, err := parser.validateTableExistence(stmt.tables())
+		if err != nil {
+			return ParsedStmt{}, err
 		}
-	}
+		scope := exprScope{tables: tables, params: &params, reads: reads}
 
-	return nil, nil
-}
+		// the columns of the FROM tables can be read but not updated
+		updated := tables[:1+len(stmt.Joined)]
+		for _, table := range updated {
+			if err := parser.checkUpdatableView(table); err != nil {
+				return ParsedStmt{}, err
+			}
+		}
+		columns, values, err := parser.validateAssignments(stmt.Set, updated, scope)
+		if err != nil {
+			return ParsedStmt{}, err
+		}
 
-// parse validates the token order and structure according to the chosen grammar.
-func (parser *SQLParser) parse(tokens []Token) (parsedStmt *ParsedStmt, err error) {
-	// Implement parsing logic to convert tokens into a ParsedStmt object.
-	var queryType QueryType
-	var tables []string
-	var columns []string
-	var values []string
-	var conditionsAndOperators []interface{}
+		err = parser.validateConditionExistence(stmt.Where, scope)
+		if err != nil {
+			return ParsedStmt{}, err
+		}
+		for _, item := range stmt.OrderBy {
+			if _, err := parser.exprType(item.Expr, scope); err != nil {
+				return ParsedStmt{}, err
+			}
+		}
+		if err := parser.checkLimit("LIMIT", stmt.Limit, &params); err != nil {
+			return ParsedStmt{}, err
+		}
+		parsedStmt.Tables = tables
+		parsedStmt.Columns = columns
+		parsedStmt.Values = exprStrings(values)
+		parsedStmt.Result, err = parser.resultColumns(stmt.Returning, scope)
+		if err != nil {
+			return ParsedStmt{}, err
+		}
+		parsedStmt.Params = params.list()
+		return parsedStmt, nil
 
-	for _, token := range tokens {
-		switch token.Type {
+	case *DeleteStmt:
+		tables, err := parser.validateTableExistence(stmt.tables())
+		if err != nil {
+			return ParsedStmt{}, err
+		}
+		if err := parser.checkUpdatableView(tables[0]); err != nil {
+			return ParsedStmt{}, err
+		}
+		scope := exprScope{tables: tables, params: &params, reads: reads}
+		err = parser.validateConditionExistence(stmt.Where, scope)
+		if err != nil {
+			return ParsedStmt{}, err
+		}
+		for _, item := range stmt.OrderBy {
+			if _, err := parser.exprType(item.Expr, scope); err != nil {
+				return ParsedStmt{}, err
+			}
+		}
+		if err := parser.checkLimit("LIMIT", stmt.Limit, &params); err != nil {
+			return ParsedStmt{}, err
+		}
+		parsedStmt.Tables = tables
+		parsedStmt.Result, err = parser.resultColumns(stmt.Returning, scope)
+		if err != nil {
+			return ParsedStmt{}, err
+		}
+		parsedStmt.Params = params.list()
+		return parsedStmt, nil
 
-		case "keyword":
-			queryType, err = paresQueryType(token.Value)
+	case *Drop:
+		if !stmt.View.Name.IsEmpty() {
+			view, err := parser.validateDropView(stmt.View)
 			if err != nil {
-				return nil, err
+				return ParsedStmt{}, err
 			}
-
-		case "table":
-			tables = append(tables, token.Value)
-
-		case "column":
-			columns = append(columns, token.Value)
-
-		case "condition":
-
-			words := strings.Fields(token.Value)
-			if len(words) != 3 {
-				return nil, errors.New("syntax Error: Invalid condition")
+			parsedStmt.Tables = []string{view}
+			return parsedStmt, nil
+		}
+		if !stmt.Index.IsEmpty() {
+			// DROP INDEX reports the table of the index
+			table, _, err := parser.Schema.lookupIndex(stmt.Index)
+			if err != nil {
+				return ParsedStmt{}, err
 			}
-			condition := Condition{Column: words[0], Operator: words[1], Value: words[2]}
-			conditionsAndOperators = append(conditionsAndOperators, condition)
-
-		case "operator":
-			op := operator{operator: token.Value}
-			conditionsAndOperators = append(conditionsAndOperators, op)
-
-		case "value":
-			values = append(values, token.Value)
-		default:
-			return nil, errors.New("syntax Error: Invalid token type")
+			parsedStmt.Tables = []string{table}
+			return parsedStmt, nil
+		}
+		tables, err := parser.validateTableExistence([]TableName{stmt.Table})
+		if err != nil {
+			return ParsedStmt{}, err
 		}
+		if parser.Schema.IsView(tables[0]) {
+			return ParsedStmt{}, fmt.Errorf("%s is a view, it is dropped by DROP VIEW", tables[0])
+		}
+		if view, ok := parser.dependentView(tables[0]); ok {
+			return ParsedStmt{}, fmt.Errorf("can not drop table %s because view %s depends on it", tables[0], view)
+		}
+		parsedStmt.Tables = tables
+		return parsedStmt, nil
 
-	}
+	case *Create:
+		if stmt.Index.IsEmpty() {
+			table, err := parser.validateCreateTable(stmt)
+			if err != nil {
+				return ParsedStmt{}, err
+			}
+			parsedStmt.Tables = []string{table}
+			return parsedStmt, nil
+		}
+		table, columns, err := parser.validateCreateIndex(stmt)
+		if err != nil {
+			return ParsedStmt{}, err
+		}
+		parsedStmt.Tables = []string{table}
+		parsedStmt.Columns = columns
+		return parsedStmt, nil
 
-	parsedStmt = &ParsedStmt{
-		QueryType:  queryType,
-		Tables:     tables,
-		Columns:    columns,
-		Values:     values,
-		Conditions: conditionsAndOperators,
-	}
-	return parsedStmt, nil
-}
+	case *CreateView:
+		view, err := parser.validateCreateView(stmt)
+		if err != nil {
+			return ParsedStmt{}, err
+		}
+		// the view comes first, followed by the tables its query reads
+		parsedStmt.Tables = append([]string{view.Name}, view.Tables...)
+		parsedStmt.Columns = nil
+		for _, column := range view.Columns {
+			parsedStmt.Columns = append(parsedStmt.Columns, column.Name)
+		}
+		parsedStmt.Result = view.Columns
+		return parsedStmt, nil
 
-// semanticAnalysis interprets the parsed structure and assigns meaning based on the schema.
-func (parser *SQLParser) semanticAnalysis(parsedStmt ParsedStmt) (res ParsedStmt, err error) {
+	case *TruncateStmt:
+		tables, err := parser.validateTableExistence(stmt.Tables)
+		if err != nil {
+			return ParsedStmt{}, err
+		}
+		for _, table := range tables {
+			if parser.Schema.IsView(table) {
+				return ParsedStmt{}, fmt.Errorf("%s is a view, it can not be truncated", table)
+			}
+		}
+		parsedStmt.Tables = tables
+		return parsedStmt, nil
 
-	tables := parsedStmt.Tables
-	Conditions := parsedStmt.Conditions
+	case *ShowStmt:
+		if stmt.Object != ShowTables {
+			tables, err := parser.validateTableExistence([]TableName{stmt.Table})
+			if err != nil {
+				return ParsedStmt{}, err
+			}
+			parsedStmt.Tables = tables
+		}
+		parsedStmt.Result = showResult(stmt)
+		return parsedStmt, nil
 
-	// check if the tables exist in the schema
-	err = parser.validateTableExistence(tables)
-	if err != nil {
-		return ParsedStmt{}, err
-	}
+	case *TransactionStmt:
+		// transaction control statements do not refer to the schema
+		return parsedStmt, nil
 
-	switch parsedStmt.QueryType {
-	case SelectQuery:
-		// check if the columns exist in the table
-		err := parser.validateColumnExistence(tables, parsedStmt.Columns)
+	case *PrepareStmt:
+		// the prepared statement gives the tables, columns and result, the
+		// parameters being typed by the declared types
+		prepared, err := parser.validatePrepare(stmt)
 		if err != nil {
 			return ParsedStmt{}, err
 		}
-		// check if the conditions exist in the table
-		err = parser.validateConditionExistence(tables, Conditions)
+		analysed := prepared.Stmt
+		analysed.QueryType = PrepareQuery
+		analysed.Stmt = stmt
+		analysed.Params = prepared.Params
+		return analysed, nil
+
+	case *ExecuteStmt:
+		// EXECUTE reads and returns what the prepared statement does
+		prepared, err := parser.validateExecute(stmt, &params)
 		if err != nil {
 			return ParsedStmt{}, err
 		}
+		parsedStmt.Tables = prepared.Stmt.Tables
+		parsedStmt.Columns = prepared.Stmt.Columns
+		parsedStmt.Params = params.list()
+		parsedStmt.Result = prepared.Stmt.Result
 		return parsedStmt, nil
 
-	case InsertQuery:
-		// check if the columns exist in the table
-		err := parser.validateColumnExistence(tables, parsedStmt.Columns)
-		if err != nil {
+	case *CreateSchema:
+		if err := parser.validateCreateSchema(stmt); err != nil {
 			return ParsedStmt{}, err
 		}
-		// check if the values count is equal to the columns count
-		if len(parsedStmt.GetColumns()) != len(parsedStmt.Values) {
-			return ParsedStmt{}, errors.New("values count does not match the columns count")
-		}
-		// check if the values are of the correct type (TODO: implement this)
 		return parsedStmt, nil
 
-	case UpdateQuery:
-
-		err := parser.validateColumnExistence(tables, parsedStmt.Columns)
+	case *DropSchema:
+		name, err := parser.validateDropSchema(stmt)
 		if err != nil {
 			return ParsedStmt{}, err
 		}
+		// the tables and views dropped with the schema
+		if name != "" {
+			parsedStmt.Tables = parser.Schema.schemaTables(name)
+		}
+		return parsedStmt, nil
 
-		err = parser.validateConditionExistence(tables, Conditions)
-		if err != nil {
+	case *UseStmt:
+		if _, err := parser.Schema.LookupSchema(stmt.Schema); err != nil {
 			return ParsedStmt{}, err
 		}
-		// check if the values are of the correct type (TODO: implement this)
+		return parsedStmt, nil
 
-		if len(parsedStmt.GetValues()) == 0 || (len(parsedStmt.GetValues()) != len(parsedStmt.Columns)) {
-			return ParsedStmt{}, errors.New("values count does not match the columns count")
+	case *SetStmt:
+		if _, err := parser.validateSet(stmt); err != nil {
+			return ParsedStmt{}, err
 		}
 		return parsedStmt, nil
 
-	case DeleteQuery:
-		err = parser.validateConditionExistence(tables, Conditions)
+	case *GrantStmt:
+		tables, err := parser.validateGrant(stmt)
 		if err != nil {
 			return ParsedStmt{}, err
 		}
+		parsedStmt.Tables = tables
 		return parsedStmt, nil
 
-	case DropQuery:
+	case *DeallocateStmt:
+		if !stmt.All {
+			if _, ok := parser.Prepared.Lookup(stmt.Name.Name); !ok {
+				return ParsedStmt{}, fmt.Errorf("prepared statement %s does not exist", stmt.Name)
+			}
+		}
 		return parsedStmt, nil
 
+	case *ExplainStmt:
+		// the explained statement is checked as if it were run, EXPLAIN
+		// itself returns the lines of the plan
+		explained, err := parser.semanticAnalysis(*flattenStatement(stmt.Stmt))
+		if err != nil {
+			return ParsedStmt{}, err
+		}
+		explained.QueryType = ExplainQuery
+		explained.Stmt = stmt
+		explained.Result = []ResultColumn{{Name: "QUERY PLAN", Type: TextType}}
+		return explained, nil
+
 	default:
 		return ParsedStmt{}, errors.New("invalid query type")
 	}
 }
 
-func (parser *SQLParser) validateTableExistence(tables []string) error {
+// validateTableExistence checks that the tables exist in the schema and
+// returns their names as stored there.
+func (parser *SQLParser) validateTableExistence(tables []TableName) ([]string, error) {
 
 	if len(tables) == 0 {
-		return fmt.Errorf("no tables specified")
+		return nil, fmt.Errorf("no tables specified")
 	}
 
-	valid, table := ContainsAll(parser.Schema.GetSchemaTables(), tables)
+	var names []string
+	for _, table := range tables {
+		name, err := parser.Schema.LookupTable(table)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
 
-	if !valid {
-		return fmt.Errorf("table %s does not exist in the schema", table)
+// validateColumnExistence checks that the columns exist in the tables and
+// returns their names as stored in the schema.
+func (parser *SQLParser) validateColumnExistence(tables []string, columns []*ColumnRef) ([]string, error) {
+	var names []string
+	for _, column := range columns {
+		_, name, err := parser.resolveColumn(tables, column)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
 	}
-	return nil
+	return names, nil
 }
 
-func (parser *SQLParser) validateColumnExistence(tables []string, columns []string) error {
-	for _, table := range tables {
-		tableColumns := parser.Schema.GetTableColumns(table)
+// validateInsertRows checks the rows of an INSERT statement against the
+// columns they fill: every row must give one value per column, of a type
+// the column can hold.
+func (parser *SQLParser) validateInsertRows(stmt *InsertStmt, table string, columns []string, params *paramSet) error {
+	switch {
+	case stmt.DefaultValues:
+		return nil
 
-		if len(tableColumns) == 0 {
-			return fmt.Errorf("table %s has no columns", table)
+	case stmt.Select != nil:
+		query, err := parser.semanticAnalysis(ParsedStmt{QueryType: SelectQuery, Stmt: stmt.Select})
+		if err != nil {
+			return err
 		}
-
-		if len(columns) == 1 && columns[0] == "*" {
-			continue
+		if len(query.Result) != len(columns) {
+			return fmt.Errorf("SELECT returns %d columns but %d columns are inserted", len(query.Result), len(columns))
 		}
-
-		valid, column := ContainsAll(tableColumns, columns)
-
-		if !valid {
-			return fmt.Errorf("column %s does not exist in table", column)
+		for i, result := range query.Result {
+			columnType := parser.Schema.columnType(table, columns[i])
+			if !assignable(result.Type, columnType) {
+				return fmt.Errorf("column %d of the SELECT is %s and does not match the type %s of column %s", i+1, result.Type, columnType, columns[i])
+			}
+		}
+		for _, param := range query.Params {
+			if err := params.addInfo(param); err != nil {
+				return err
+			}
 		}
+		return nil
 	}
-	return nil
-}
-
-func (parser *SQLParser) validateConditionExistence(tables []string, conditions []interface{}) error {
-	for _, table := range tables {
-		tableColumns := parser.Schema.GetTableColumns(table)
 
-		if len(tableColumns) == 0 {
-			return fmt.Errorf("table %s has no columns", table)
-		}
-
-		for _, item := range conditions {
-			switch elem := item.(type) {
-				case Condition:
-					valid, column := ContainsAll(tableColumns, []string{elem.Column})
-					if !valid {
-						return fmt.Errorf("column %s in Condition does not exist in table", column)
-					}
-					// check if the operator is valid
-					valid, operator := ContainsAll([]string{"=", ">", "<", ">=", "<=", "!="}, []string{elem.Operator})
-					if !valid {
-						return fmt.Errorf("invalid operator %s in Condition", operator)
-					}
-				case operator:
-					valid, operator := ContainsAll([]string{"AND", "OR"}, []string{elem.operator})
-					if !valid {
-						return fmt.Errorf("invalid operator %s", operator)
-					}
-				default: 
-					return fmt.Errorf("invalid condition type")
+	for i, row := range stmt.Rows {
+		// check if the values count is equal to the columns count
+		if len(row) != len(columns) {
+			return fmt.Errorf("values count does not match the columns count in row %d", i+1)
+		}
+		for j, value := range row {
+			if column, ok := value.(*ColumnRef); ok {
+				return fmt.Errorf("column %s can not be used in VALUES", column)
+			}
+			// the values can not reference columns
+			err := parser.checkValue(value, table, columns[j], exprScope{params: params})
+			if err != nil && len(stmt.Rows) > 1 {
+				return fmt.Errorf("row %d: %v", i+1, err)
+			}
+			if err != nil {
+				return err
 			}
 		}
 	}
 	return nil
 }
 
-func parseCondition(token []Token, sql string) ([]Token, error) {
-	// get the substring after the first "WHERE" keyword
-	// WHERE condition1 AND condition2 AND condition3 ...;
-	var conditionString string = strings.Split(sql, "WHERE")[1]
-	conditionString = strings.Replace(conditionString, " ", "", -1)
-	conditionString = strings.Replace(conditionString, ";", "", -1)
-	if len(conditionString) == 0 {
-		return nil, errors.New("missing condition in WHERE clause")
+// resolveColumn finds the table, among the resolved tables of the
+// statement, holding the column and returns the table and column names as
+// stored in the schema. An unqualified column held by several of the tables
+// is ambiguous.
+func (parser *SQLParser) resolveColumn(tables []string, column *ColumnRef) (table, name string, err error) {
+	if !column.Schema.IsEmpty() {
+		if _, err := parser.Schema.LookupSchema(column.Schema); err != nil {
+			return "", "", err
+		}
 	}
 
-	// split the string by the AND or OR keyword
-	andConditions := strings.Split(strings.ToLower(conditionString), "and")
-	orConditions := strings.Split(strings.ToLower(conditionString), "or")
+	var searched []string
+	for _, candidate := range tables {
+		if !column.Table.IsEmpty() && !parser.Schema.matchesTable(column.Schema, column.Table, candidate) {
+			continue
+		}
+		if containsString(searched, candidate) {
+			continue
+		}
+		searched = append(searched, candidate)
 
-	if len(andConditions) > 1 {
-		for _, cond := range andConditions {
-			token = append(token, Token{Type: "condition", Value: strings.Replace(cond, " ", "", -1)})
-			token = append(token, Token{Type: "operator", Value: "AND"})
+		tableColumns := parser.Schema.GetTableColumns(candidate)
+		if len(tableColumns) == 0 {
+			return "", "", fmt.Errorf("table %s has no columns", candidate)
+		}
+		resolved, ok := column.Column.resolve(tableColumns)
+		if !ok {
+			continue
 		}
-		token = token[:len(token)-1]
-	} else if len(orConditions) > 1 {
-		for _, cond := range orConditions {
-			token = append(token, Token{Type: "condition", Value: strings.Replace(cond, " ", "", -1)})
-			token = append(token, Token{Type: "operator", Value: "OR"})
+		if table != "" {
+			return "", "", fmt.Errorf("column %s is ambiguous, it exists in tables %s and %s", column, table, candidate)
 		}
-		token = token[:len(token)-1]
-	} else {
-		token = append(token, Token{Type: "condition", Value: strings.Replace(conditionString, " ", "", -1)})
+		table, name = candidate, resolved
+	}
+	switch {
+	case table != "":
+		return table, name, nil
+	case len(searched) == 0:
+		return "", "", fmt.Errorf("table %s of column %s is not part of the statement", column.Table, column)
+	case len(searched) == 1:
+		return "", "", fmt.Errorf("column %s does not exist in table %s", column.Column, searched[0])
 	}
+	return "", "", fmt.Errorf("column %s does not exist in tables %s", column.Column, strings.Join(searched, ", "))
+}
 
-	return token, nil
+// columnRefs turns plain column names into column references.
+func columnRefs(ids []Identifier) []*ColumnRef {
+	refs := make([]*ColumnRef, 0, len(ids))
+	for _, id := range ids {
+		refs = append(refs, &ColumnRef{Column: id})
+	}
+	return refs
 }
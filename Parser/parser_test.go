@@ -0,0 +1,87 @@
+package sqlParser
+
+import (
+	"strings"
+	"testing"
+)
+
+// testSchema returns the schema the tests are analysed against: USERS and
+// ORDERS, with the types of their columns and their indexes.
+func testSchema() Schema {
+	return Schema{
+		Name: "PUBLIC",
+		Tables: map[string][]string{
+			"USERS":  {"ID", "NAME", "AGE", "EMAIL"},
+			"ORDERS": {"ID", "USER_ID", "TOTAL", "CREATED_AT"},
+		},
+		ColumnTypes: map[string]map[string]string{
+			"USERS":  {"ID": "bigint", "NAME": "varchar(50)", "AGE": "int", "EMAIL": "text"},
+			"ORDERS": {"ID": "bigint", "USER_ID": "bigint", "TOTAL": "decimal(10,2)", "CREATED_AT": "timestamp"},
+		},
+		Indexes: map[string][]Index{
+			"USERS": {
+				{Name: "USERS_PK", Columns: []string{"ID"}, Primary: true},
+				{Name: "USERS_EMAIL", Columns: []string{"EMAIL"}, Unique: true},
+			},
+			"ORDERS": {{Name: "ORDERS_PK", Columns: []string{"ID"}, Primary: true}},
+		},
+	}
+}
+
+// parseTest is a statement and the error parsing it gives.
+type parseTest struct {
+	name string
+	sql  string
+	err  string // a part of the error message, empty when the statement is accepted
+}
+
+// runParseTests parses each statement with a parser of its own, made by
+// newParser, and checks the error it gives.
+func runParseTests(t *testing.T, newParser func() *SQLParser, tests []parseTest) {
+	t.Helper()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := newParser().ParseSQL(test.sql)
+			checkError(t, err, test.err)
+		})
+	}
+}
+
+// newTestParser returns a parser of the generic dialect on the test schema.
+func newTestParser() *SQLParser {
+	return NewSQLParser(testSchema())
+}
+
+// newDialectParser returns a function making parsers of the dialect on the
+// test schema, for runParseTests.
+func newDialectParser(dialect Dialect) func() *SQLParser {
+	return func() *SQLParser {
+		parser := newTestParser()
+		parser.Dialect = dialect
+		return parser
+	}
+}
+
+// mustParse parses the statement, failing the test on an error.
+func mustParse(t *testing.T, parser *SQLParser, sql string) ParsedStmt {
+	t.Helper()
+	parsedStmt, err := parser.ParseSQL(sql)
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", sql, err)
+	}
+	return parsedStmt
+}
+
+// checkError fails the test when err is not the expected one: nil when want
+// is empty, an error containing want otherwise.
+func checkError(t *testing.T, err error, want string) {
+	t.Helper()
+	switch {
+	case want == "" && err != nil:
+		t.Fatalf("unexpected error: %v", err)
+	case want != "" && err == nil:
+		t.Fatalf("expected an error containing %q, got none", want)
+	case want != "" && !strings.Contains(err.Error(), want):
+		t.Fatalf("expected an error containing %q, got %q", want, err)
+	}
+}
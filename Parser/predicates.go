@@ -0,0 +1,273 @@
+package sqlParser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Comparison compares two values: left op right
+type Comparison struct {
+	Trivia
+	Left     Expr
+	Operator string // =, <>, !=, <, >, <= or >=
+	Right    Expr
+}
+
+func (expr *Comparison) String() string {
+	return expr.Left.String() + " " + expr.Operator + " " + expr.Right.String()
+}
+
+// LogicalExpr joins two conditions: left AND right, left OR right
+type LogicalExpr struct {
+	Trivia
+	Left     Expr
+	Operator string // AND or OR
+	Right    Expr
+}
+
+func (expr *LogicalExpr) String() string {
+	return expr.Left.String() + " " + expr.Operator + " " + expr.Right.String()
+}
+
+// NotExpr negates a condition: NOT expr
+type NotExpr struct {
+	Trivia
+	Expr Expr
+}
+
+func (expr *NotExpr) String() string {
+	return "NOT " + expr.Expr.String()
+}
+
+// InExpr tests the membership of a value in a list: expr [NOT] IN (v1, v2, ...)
+type InExpr struct {
+	Trivia
+	Expr Expr
+	Not  bool
+	List []Expr
+}
+
+func (expr *InExpr) String() string {
+	return expr.Expr.String() + not(expr.Not) + " IN (" + strings.Join(exprStrings(expr.List), ", ") + ")"
+}
+
+// BetweenExpr tests a value against a range: expr [NOT] BETWEEN low AND high
+type BetweenExpr struct {
+	Trivia
+	Expr Expr
+	Not  bool
+	Low  Expr
+	High Expr
+}
+
+func (expr *BetweenExpr) String() string {
+	return expr.Expr.String() + not(expr.Not) + " BETWEEN " + expr.Low.String() + " AND " + expr.High.String()
+}
+
+// LikeExpr matches a text against a pattern:
+// expr [NOT] LIKE | ILIKE pattern [ESCAPE escape]
+type LikeExpr struct {
+	Trivia
+	Expr     Expr
+	Not      bool
+	Operator string // LIKE, or ILIKE to ignore case
+	Pattern  Expr
+	Escape   Expr // nil without ESCAPE
+}
+
+func (expr *LikeExpr) String() string {
+	str := expr.Expr.String() + not(expr.Not) + " " + expr.Operator + " " + expr.Pattern.String()
+	if expr.Escape != nil {
+		str += " ESCAPE " + expr.Escape.String()
+	}
+	return str
+}
+
+// IsNullExpr tests whether a value is NULL: expr IS [NOT] NULL
+type IsNullExpr struct {
+	Trivia
+	Expr Expr
+	Not  bool
+}
+
+func (expr *IsNullExpr) String() string {
+	if expr.Not {
+		return expr.Expr.String() + " IS NOT NULL"
+	}
+	return expr.Expr.String() + " IS NULL"
+}
+
+// IsDistinctExpr compares two values, NULL being equal to NULL:
+// left IS [NOT] DISTINCT FROM right
+type IsDistinctExpr struct {
+	Trivia
+	Left  Expr
+	Not   bool
+	Right Expr
+}
+
+func (expr *IsDistinctExpr) String() string {
+	return expr.Left.String() + " IS" + not(expr.Not) + " DISTINCT FROM " + expr.Right.String()
+}
+
+func (*BetweenExpr) exprNode()    {}
+func (*Comparison) exprNode()     {}
+func (*InExpr) exprNode()         {}
+func (*IsDistinctExpr) exprNode() {}
+func (*IsNullExpr) exprNode()     {}
+func (*LikeExpr) exprNode()       {}
+func (*LogicalExpr) exprNode()    {}
+func (*NotExpr) exprNode()        {}
+
+// not returns the NOT of a negated predicate.
+func not(negated bool) string {
+	if negated {
+		return " NOT"
+	}
+	return ""
+}
+
+// predicateType checks a predicate and returns its type, BOOLEAN.
+func (parser *SQLParser) predicateType(expr Expr, scope exprScope) (DataType, error) {
+	switch expr := expr.(type) {
+	case *Comparison:
+		if err := parser.checkComparable(expr.Left, expr.Right, expr.Operator, scope); err != nil {
+			return UnknownType, err
+		}
+	case *IsDistinctExpr:
+		if err := parser.checkComparable(expr.Left, expr.Right, "IS DISTINCT FROM", scope); err != nil {
+			return UnknownType, err
+		}
+	case *InExpr:
+		for _, value := range expr.List {
+			if err := parser.checkComparable(expr.Expr, value, "IN", scope); err != nil {
+				return UnknownType, err
+			}
+		}
+	case *BetweenExpr:
+		if err := parser.checkComparable(expr.Expr, expr.Low, "BETWEEN", scope); err != nil {
+			return UnknownType, err
+		}
+		if err := parser.checkComparable(expr.Expr, expr.High, "BETWEEN", scope); err != nil {
+			return UnknownType, err
+		}
+	case *LikeExpr:
+		for _, operand := range []Expr{expr.Expr, expr.Pattern, expr.Escape} {
+			if operand == nil {
+				continue
+			}
+			if err := parser.checkText(operand, expr.Operator, scope); err != nil {
+				return UnknownType, err
+			}
+		}
+		if lit, ok := expr.Escape.(*Literal); ok && lit.Kind == StringLiteral && len([]rune(lit.Value.(string))) != 1 {
+			return UnknownType, fmt.Errorf("ESCAPE %s must be a single character", lit.Raw)
+		}
+	case *IsNullExpr:
+		if _, err := parser.exprType(expr.Expr, scope); err != nil {
+			return UnknownType, err
+		}
+	case *LogicalExpr:
+		if err := parser.checkBoolean(expr.Left, expr.Operator, scope); err != nil {
+			return UnknownType, err
+		}
+		if err := parser.checkBoolean(expr.Right, expr.Operator, scope); err != nil {
+			return UnknownType, err
+		}
+	case *NotExpr:
+		if err := parser.checkBoolean(expr.Expr, "NOT", scope); err != nil {
+			return UnknownType, err
+		}
+	}
+	return BooleanType, nil
+}
+
+// checkComparable verifies that two values can be compared, a parameter
+// takes the type of the value it is compared to.
+func (parser *SQLParser) checkComparable(left, right Expr, operator string, scope exprScope) error {
+	leftType, err := parser.exprType(left, scope)
+	if err != nil {
+		return err
+	}
+	rightType, err := parser.exprType(right, scope)
+	if err != nil {
+		return err
+	}
+	if param, ok := left.(*Param); ok {
+		return parser.bindParam(param, right, rightType, scope)
+	}
+	if param, ok := right.(*Param); ok {
+		return parser.bindParam(param, left, leftType, scope)
+	}
+	if _, ok := commonType(leftType, rightType); !ok {
+		return fmt.Errorf("%s can not compare %s of type %s with %s of type %s", operator, left, leftType, right, rightType)
+	}
+	return nil
+}
+
+// checkText verifies that the operand of a text operator is a text.
+func (parser *SQLParser) checkText(operand Expr, operator string, scope exprScope) error {
+	typ, err := parser.exprType(operand, scope)
+	if err != nil {
+		return err
+	}
+	if param, ok := operand.(*Param); ok {
+		return scope.params.add(param, "", "", TextType)
+	}
+	if typ != TextType && typ != UnknownType && typ != NullType {
+		return fmt.Errorf("%s can not be applied to %s of type %s", operator, operand, typ)
+	}
+	return nil
+}
+
+// checkBoolean verifies that the operand of a logical operator is a
+// condition.
+func (parser *SQLParser) checkBoolean(operand Expr, operator string, scope exprScope) error {
+	typ, err := parser.exprType(operand, scope)
+	if err != nil {
+		return err
+	}
+	if param, ok := operand.(*Param); ok {
+		return scope.params.add(param, "", "", BooleanType)
+	}
+	if typ != BooleanType && typ != UnknownType && typ != NullType {
+		return fmt.Errorf("argument of %s must be BOOLEAN, not %s of type %s", operator, operand, typ)
+	}
+	return nil
+}
+
+// bindParam gives a parameter the type of the value it is used with, and the
+// table and column of the value when it is a column.
+func (parser *SQLParser) bindParam(param *Param, value Expr, typ DataType, scope exprScope) error {
+	if column, ok := value.(*ColumnRef); ok {
+		table, name, err := scope.resolveColumn(parser, column)
+		if err != nil {
+			return err
+		}
+		return scope.params.add(param, table, name, typ)
+	}
+	return scope.params.add(param, "", "", typ)
+}
+
+// validateConditionExistence checks that the columns of the condition exist
+// and that the condition is a boolean expression.
+func (parser *SQLParser) validateConditionExistence(where Expr, scope exprScope) error {
+	if where == nil {
+		return nil
+	}
+	return parser.checkBoolean(where, "WHERE", scope)
+}
+
+// flattenConditions lists the conditions joined by the AND and OR operators
+// at the top of the expression, together with the operators, in order.
+func flattenConditions(where Expr) []interface{} {
+	switch where := where.(type) {
+	case nil:
+		return nil
+	case *LogicalExpr:
+		conditions := flattenConditions(where.Left)
+		conditions = append(conditions, operator{operator: where.Operator})
+		return append(conditions, flattenConditions(where.Right)...)
+	}
+	return []interface{}{where}
+}
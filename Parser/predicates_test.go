@@ -0,0 +1,74 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPredicates(t *testing.T) {
+	runParseTests(t, newTestParser, []parseTest{
+		{name: "in", sql: "SELECT id FROM users WHERE age IN (18, 21) AND id NOT IN (1, 2)"},
+		{name: "between", sql: "SELECT id FROM users WHERE age BETWEEN 18 AND 65 OR age NOT BETWEEN 1 AND 2"},
+		{name: "like", sql: "SELECT id FROM users WHERE name LIKE 'a%' AND email NOT LIKE '%!_%' ESCAPE '!'"},
+		{name: "ilike", sql: "SELECT id FROM users WHERE name ILIKE 'ann'"},
+		{name: "is null", sql: "SELECT id FROM users WHERE email IS NULL OR name IS NOT NULL"},
+		{name: "is distinct from", sql: "SELECT id FROM users WHERE name IS DISTINCT FROM email"},
+		{name: "not", sql: "SELECT id FROM users WHERE NOT (age > 1 AND name IS NOT NULL)"},
+		{name: "date comparison", sql: "SELECT id FROM orders WHERE created_at >= DATE '2024-01-01'"},
+		{name: "comparison type", sql: "SELECT id FROM users WHERE age = 'x'", err: "= can not compare AGE of type INTEGER with 'x' of type TEXT"},
+		{name: "in type", sql: "SELECT id FROM users WHERE age IN (1, 'a')", err: "IN can not compare AGE of type INTEGER with 'a' of type TEXT"},
+		{name: "between type", sql: "SELECT id FROM users WHERE age BETWEEN 1 AND 'x'", err: "BETWEEN can not compare AGE of type INTEGER with 'x' of type TEXT"},
+		{name: "like type", sql: "SELECT id FROM users WHERE name LIKE 1", err: "LIKE can not be applied to 1 of type INTEGER"},
+		{name: "escape length", sql: "SELECT id FROM users WHERE name LIKE 'a' ESCAPE 'ab'", err: "ESCAPE 'ab' must be a single character"},
+		{name: "not of a number", sql: "SELECT id FROM users WHERE NOT age", err: "argument of NOT must be BOOLEAN, not AGE of type INTEGER"},
+		{name: "where of a number", sql: "SELECT id FROM users WHERE age", err: "argument of WHERE must be BOOLEAN, not AGE of type INTEGER"},
+		{name: "unknown column", sql: "SELECT id FROM users WHERE nope IS NULL", err: "column NOPE does not exist in table USERS"},
+	})
+}
+
+func TestConditions(t *testing.T) {
+	parsedStmt := mustParse(t, newTestParser(), "SELECT id FROM users WHERE age IN (1, 2) AND name NOT LIKE 'a%' OR email IS NULL")
+	var conditions []string
+	for _, condition := range parsedStmt.Conditions {
+		switch condition := condition.(type) {
+		case operator:
+			conditions = append(conditions, condition.operator)
+		case Expr:
+			conditions = append(conditions, condition.String())
+		}
+	}
+	want := []string{"AGE IN (1, 2)", "AND", "NAME NOT LIKE 'a%'", "OR", "EMAIL IS NULL"}
+	if !reflect.DeepEqual(conditions, want) {
+		t.Fatalf("expected the conditions %q, got %q", want, conditions)
+	}
+	if where := parsedStmt.Stmt.(*SelectStmt).Where.String(); where != "AGE IN (1, 2) AND NAME NOT LIKE 'a%' OR EMAIL IS NULL" {
+		t.Fatalf("unexpected WHERE clause %s", where)
+	}
+}
+
+func TestPredicateComments(t *testing.T) {
+	parser := newTestParser()
+	parser.KeepComments = true
+	parsedStmt := mustParse(t, parser, "SELECT id FROM users WHERE email IS NULL /* no email */ AND age BETWEEN 18 AND 65 /* adults */ AND name IS NOT NULL -- named")
+	and := parsedStmt.Stmt.(*SelectStmt).Where.(*LogicalExpr)
+	first := and.Left.(*LogicalExpr)
+	between := first.Right.(*BetweenExpr)
+	tests := []struct {
+		node     string
+		comments []Comment
+		want     string
+	}{
+		{"IS NULL", first.Left.(*IsNullExpr).Comments, "/* no email */"},
+		{"upper bound", between.High.(*Literal).Comments, "/* adults */"},
+		{"IS NOT NULL", and.Right.(*IsNullExpr).Comments, "-- named"},
+	}
+	for _, test := range tests {
+		if len(test.comments) != 1 || test.comments[0].Text != test.want {
+			t.Errorf("expected the %s comment %q, got %+v", test.node, test.want, test.comments)
+		}
+	}
+	// the comment following a predicate is not given to the next one
+	if comments := between.Expr.(*ColumnRef).Comments; comments != nil {
+		t.Errorf("expected no comment on AGE, got %+v", comments)
+	}
+}
@@ -0,0 +1,164 @@
+package sqlParser
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PrepareStmt represents a PREPARE statement, which analyses a statement
+// once and stores it under a name for the EXECUTE statements of the session.
+type PrepareStmt struct {
+	Trivia
+	Name  Identifier
+	Types []string  // the declared types of the parameters $1, $2, ... as written
+	Stmt  Statement // the prepared statement
+}
+
+// ExecuteStmt represents an EXECUTE statement, which runs a prepared
+// statement with the given arguments.
+type ExecuteStmt struct {
+	Trivia
+	Name Identifier
+	Args []Expr // one value per parameter of the prepared statement
+}
+
+// DeallocateStmt represents a DEALLOCATE statement, which forgets one or all
+// the prepared statements of the session.
+type DeallocateStmt struct {
+	Trivia
+	Name Identifier // empty with ALL
+	All  bool
+}
+
+func (stmt *PrepareStmt) QueryType() QueryType    { return PrepareQuery }
+func (stmt *ExecuteStmt) QueryType() QueryType    { return ExecuteQuery }
+func (stmt *DeallocateStmt) QueryType() QueryType { return DeallocateQuery }
+
+// PreparedStatement is a statement stored by PREPARE. Its parameters are the
+// ones inferred by the semantic analysis, with the declared types of PREPARE
+// taking precedence, and EXECUTE takes one argument per parameter in their
+// order.
+type PreparedStatement struct {
+	Name   string
+	Stmt   ParsedStmt // the analysed statement
+	Params []ParamInfo
+}
+
+// PreparedRegistry holds the prepared statements of a session, which is the
+// lifetime of a SQLParser. The names are stored as written, unquoted names
+// in upper case.
+type PreparedRegistry struct {
+	statements map[string]PreparedStatement
+}
+
+// Lookup returns the prepared statement of the given name.
+func (registry *PreparedRegistry) Lookup(name string) (PreparedStatement, bool) {
+	prepared, ok := registry.statements[name]
+	return prepared, ok
+}
+
+// Names returns the names of the prepared statements, sorted.
+func (registry *PreparedRegistry) Names() []string {
+	names := make([]string, 0, len(registry.statements))
+	for name := range registry.statements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (registry *PreparedRegistry) put(prepared PreparedStatement) {
+	if registry.statements == nil {
+		registry.statements = map[string]PreparedStatement{}
+	}
+	registry.statements[prepared.Name] = prepared
+}
+
+func (registry *PreparedRegistry) remove(name string) {
+	delete(registry.statements, name)
+}
+
+func (registry *PreparedRegistry) clear() {
+	registry.statements = nil
+}
+
+// validatePrepare analyses the prepared statement and returns it with its
+// parameters. A declared type must match the type inferred for its
+// parameter, and a declared parameter the statement does not use still
+// takes an argument.
+func (parser *SQLParser) validatePrepare(stmt *PrepareStmt) (PreparedStatement, error) {
+	if _, ok := parser.Prepared.Lookup(stmt.Name.Name); ok {
+		return PreparedStatement{}, fmt.Errorf("prepared statement %s already exists", stmt.Name)
+	}
+	switch stmt.Stmt.(type) {
+	case *SelectStmt, *InsertStmt, *UpdateStmt, *DeleteStmt:
+	default:
+		return PreparedStatement{}, fmt.Errorf("%s statements can not be prepared", stmt.Stmt.QueryType())
+	}
+
+	analysed, err := parser.semanticAnalysis(*flattenStatement(stmt.Stmt))
+	if err != nil {
+		return PreparedStatement{}, err
+	}
+	params := append([]ParamInfo(nil), analysed.Params...)
+	for i, typeName := range stmt.Types {
+		declared := ParseDataType(typeName)
+		if declared == UnknownType {
+			return PreparedStatement{}, fmt.Errorf("type %s does not exist", typeName)
+		}
+		index := i + 1
+		found := false
+		for j := range params {
+			if params[j].Index != index {
+				continue
+			}
+			found = true
+			if _, ok := commonType(params[j].Type, declared); !ok {
+				return PreparedStatement{}, fmt.Errorf("parameter %s is declared as %s but used as %s", params[j].Raw, declared, params[j].Type)
+			}
+			params[j].Type = declared
+		}
+		if !found {
+			params = append(params, ParamInfo{Raw: fmt.Sprintf("$%d", index), Index: index, Type: declared})
+		}
+	}
+	set := paramSet{params: params}
+	return PreparedStatement{Name: stmt.Name.Name, Stmt: analysed, Params: set.list()}, nil
+}
+
+// validateExecute checks the arguments of EXECUTE against the parameters of
+// the prepared statement: one argument per parameter, of a type the
+// parameter accepts. Parameters given as arguments take the type of the
+// parameter they are bound to.
+func (parser *SQLParser) validateExecute(stmt *ExecuteStmt, params *paramSet) (PreparedStatement, error) {
+	prepared, ok := parser.Prepared.Lookup(stmt.Name.Name)
+	if !ok {
+		return PreparedStatement{}, fmt.Errorf("prepared statement %s does not exist", stmt.Name)
+	}
+	if len(stmt.Args) != len(prepared.Params) {
+		return PreparedStatement{}, fmt.Errorf("prepared statement %s takes %d arguments but %d were given", stmt.Name, len(prepared.Params), len(stmt.Args))
+	}
+
+	scope := exprScope{params: params}
+	for i, arg := range stmt.Args {
+		expected := prepared.Params[i]
+		typ, err := parser.exprType(arg, scope)
+		if err != nil {
+			return PreparedStatement{}, err
+		}
+		if param, ok := arg.(*Param); ok {
+			if err := params.add(param, expected.Table, expected.Column, expected.Type); err != nil {
+				return PreparedStatement{}, err
+			}
+			continue
+		}
+		if expected.Type == UnknownType || assignable(typ, expected.Type) {
+			continue
+		}
+		if lit, ok := arg.(*Literal); ok && lit.Kind == StringLiteral && convertible(lit.Value.(string), expected.Type) {
+			continue
+		}
+		return PreparedStatement{}, fmt.Errorf("argument %s of type %s does not match the type %s of parameter %s", arg, typ, expected.Type, expected.Raw)
+	}
+	return prepared, nil
+}
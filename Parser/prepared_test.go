@@ -0,0 +1,85 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newPreparedParser returns a parser on the test schema with the statements
+// OLDER_THAN, RENAME and ALL_USERS prepared.
+func newPreparedParser() *SQLParser {
+	parser := newTestParser()
+	for _, sql := range []string{
+		"PREPARE older_than AS SELECT id, name FROM users WHERE age > $1",
+		"PREPARE rename (text, bigint) AS UPDATE users SET name = $1 WHERE id = $2",
+		"PREPARE all_users AS SELECT id FROM users",
+	} {
+		if _, err := parser.ParseSQL(sql); err != nil {
+			panic(err)
+		}
+	}
+	return parser
+}
+
+func TestPreparedStatements(t *testing.T) {
+	runParseTests(t, newPreparedParser, []parseTest{
+		{name: "prepare select", sql: "PREPARE by_name AS SELECT id FROM users WHERE name = $1"},
+		{name: "prepare insert", sql: "PREPARE add_order (bigint, bigint, decimal) AS INSERT INTO orders (id, user_id, total) VALUES ($1, $2, $3)"},
+		{name: "prepare delete", sql: "PREPARE forget AS DELETE FROM orders WHERE id = $1"},
+		{name: "unused declared parameter", sql: "PREPARE p (int) AS SELECT id FROM users"},
+		{name: "execute", sql: "EXECUTE older_than (18)"},
+		{name: "execute with a string", sql: "EXECUTE older_than ('18')"},
+		{name: "execute with parameters", sql: "EXECUTE rename ('ada', 1)"},
+		{name: "execute with bind parameters", sql: "EXECUTE rename ($1, $2)"},
+		{name: "execute without arguments", sql: "EXECUTE all_users"},
+		{name: "deallocate", sql: "DEALLOCATE older_than"},
+		{name: "deallocate prepare", sql: "DEALLOCATE PREPARE rename"},
+		{name: "deallocate all", sql: "DEALLOCATE ALL"},
+		{name: "prepared twice", sql: "PREPARE older_than AS SELECT id FROM users", err: "prepared statement OLDER_THAN already exists"},
+		{name: "prepare a transaction", sql: "PREPARE p AS BEGIN", err: "TRANSACTION statements can not be prepared"},
+		{name: "prepare without name", sql: "PREPARE AS SELECT id FROM users", err: "a prepared statement name"},
+		{name: "prepare without as", sql: "PREPARE p SELECT id FROM users", err: "AS"},
+		{name: "invalid prepared statement", sql: "PREPARE p AS SELECT nope FROM users", err: "column NOPE does not exist"},
+		{name: "unknown declared type", sql: "PREPARE p (nope) AS SELECT id FROM users WHERE id = $1", err: "type NOPE does not exist"},
+		{name: "declared type mismatch", sql: "PREPARE p (date) AS SELECT id FROM users WHERE age = $1", err: "parameter $1 is declared as DATE but used as INTEGER"},
+		{name: "execute unknown", sql: "EXECUTE nope (1)", err: "prepared statement NOPE does not exist"},
+		{name: "too few arguments", sql: "EXECUTE rename ('ada')", err: "prepared statement RENAME takes 2 arguments but 1 were given"},
+		{name: "too many arguments", sql: "EXECUTE all_users (1)", err: "prepared statement ALL_USERS takes 0 arguments but 1 were given"},
+		{name: "argument type", sql: "EXECUTE older_than (TRUE)", err: "argument TRUE of type BOOLEAN does not match the type INTEGER of parameter $1"},
+		{name: "execute without name", sql: "EXECUTE", err: "a prepared statement name"},
+		{name: "deallocate unknown", sql: "DEALLOCATE nope", err: "prepared statement NOPE does not exist"},
+	})
+}
+
+func TestPreparedRegistry(t *testing.T) {
+	parser := newPreparedParser()
+	if want := []string{"ALL_USERS", "OLDER_THAN", "RENAME"}; !reflect.DeepEqual(parser.Prepared.Names(), want) {
+		t.Fatalf("expected the prepared statements %v, got %v", want, parser.Prepared.Names())
+	}
+
+	prepared, _ := parser.Prepared.Lookup("RENAME")
+	var types []DataType
+	for _, param := range prepared.Params {
+		types = append(types, param.Type)
+	}
+	if want := []DataType{TextType, IntegerType}; !reflect.DeepEqual(types, want) {
+		t.Fatalf("expected the parameter types %v, got %v", want, types)
+	}
+
+	parsedStmt := mustParse(t, parser, "EXECUTE older_than (18)")
+	if want := []string{"USERS"}; !reflect.DeepEqual(parsedStmt.Tables, want) {
+		t.Fatalf("expected the tables %v, got %v", want, parsedStmt.Tables)
+	}
+	if len(parsedStmt.Result) != 2 || parsedStmt.Result[1].Name != "NAME" {
+		t.Fatalf("expected the result of the prepared statement, got %v", parsedStmt.Result)
+	}
+
+	mustParse(t, parser, "DEALLOCATE rename")
+	_, err := parser.ParseSQL("EXECUTE rename ('ada', 1)")
+	checkError(t, err, "prepared statement RENAME does not exist")
+
+	mustParse(t, parser, "DEALLOCATE ALL")
+	if names := parser.Prepared.Names(); len(names) != 0 {
+		t.Fatalf("expected no prepared statement, got %v", names)
+	}
+}
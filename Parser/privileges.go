@@ -0,0 +1,474 @@
+package sqlParser
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Privilege is a right on a table, or on some columns of a table.
+type Privilege string
+
+const (
+	SelectPrivilege     Privilege = "SELECT"
+	InsertPrivilege     Privilege = "INSERT"
+	UpdatePrivilege     Privilege = "UPDATE"
+	DeletePrivilege     Privilege = "DELETE"
+	TruncatePrivilege   Privilege = "TRUNCATE"
+	ReferencesPrivilege Privilege = "REFERENCES"
+	TriggerPrivilege    Privilege = "TRIGGER"
+	AllPrivileges       Privilege = "ALL" // every privilege of the table, or of the columns
+)
+
+// tablePrivileges are the privileges ALL stands for on a table.
+var tablePrivileges = []Privilege{
+	SelectPrivilege, InsertPrivilege, UpdatePrivilege, DeletePrivilege,
+	TruncatePrivilege, ReferencesPrivilege, TriggerPrivilege,
+}
+
+// columnPrivileges are the privileges that can be granted on columns, the
+// ones ALL stands for on columns.
+var columnPrivileges = []Privilege{SelectPrivilege, InsertPrivilege, UpdatePrivilege, ReferencesPrivilege}
+
+// PrivilegeItem is a privilege of a GRANT or REVOKE, on the listed columns
+// or on the whole table.
+type PrivilegeItem struct {
+	Privilege Privilege
+	Columns   []Identifier // empty for the whole table
+}
+
+// GrantStmt represents a GRANT or REVOKE statement, of privileges on tables
+// or of roles, to users and roles.
+type GrantStmt struct {
+	Trivia
+	Revoke      bool
+	Privileges  []PrivilegeItem // the privileges on Tables, empty when roles are granted
+	Tables      []TableName
+	Roles       []Identifier // the roles granted, empty when privileges are
+	Grantees    []Identifier // PUBLIC stands for every user
+	GrantOption bool         // WITH GRANT | ADMIN OPTION, or GRANT | ADMIN OPTION FOR of REVOKE
+	Cascade     bool
+}
+
+func (stmt *GrantStmt) QueryType() QueryType {
+	if stmt.Revoke {
+		return RevokeQuery
+	}
+	return GrantQuery
+}
+
+// PublicRole is the role every user is a member of.
+const PublicRole = "PUBLIC"
+
+// Grant is a privilege held by a user or role on a table, or on some columns
+// of the table.
+type Grant struct {
+	Grantee     string
+	Privilege   Privilege // never AllPrivileges, ALL grants each privilege
+	Table       string
+	Columns     []string // nil for the whole table
+	GrantOption bool     // the grantee may grant the privilege to others
+}
+
+// Membership makes a user or role a member of a role, which gives it the
+// privileges of the role.
+type Membership struct {
+	Role        string
+	Member      string
+	AdminOption bool // the member may grant the role to others
+}
+
+// Privileges is the privilege model of the database. When SQLParser.Privileges
+// is set, the semantic analysis rejects the statements the current user may
+// not run, and GRANT and REVOKE update the model. The names are stored as in
+// the schema, unquoted names in upper case.
+type Privileges struct {
+	User        string            // the current user
+	Owners      map[string]string // maps the tables and views to the user owning them, who holds every privilege on them
+	RoleOwners  map[string]string // maps the roles to the user owning them, who may grant them to others
+	Grants      []Grant
+	Memberships []Membership
+}
+
+// roles returns the user and the roles it is a member of, directly or
+// through other roles.
+func (privileges *Privileges) roles(user string) []string {
+	roles := []string{user, PublicRole}
+	for i := 0; i < len(roles); i++ {
+		for _, membership := range privileges.Memberships {
+			if membership.Member == roles[i] && !containsString(roles, membership.Role) {
+				roles = append(roles, membership.Role)
+			}
+		}
+	}
+	return roles
+}
+
+// IsOwner reports whether the user owns the table or view, or is a member
+// of the role owning it.
+func (privileges *Privileges) IsOwner(user, table string) bool {
+	owner, ok := privileges.Owners[table]
+	return ok && containsString(privileges.roles(user), owner)
+}
+
+// Allowed reports whether the user holds the privilege on the column of the
+// table. An empty column asks for the privilege on the whole table or on
+// any of its columns.
+func (privileges *Privileges) Allowed(user string, privilege Privilege, table, column string) bool {
+	if privileges.IsOwner(user, table) {
+		return true
+	}
+	roles := privileges.roles(user)
+	for _, grant := range privileges.Grants {
+		if grant.Privilege != privilege || grant.Table != table || !containsString(roles, grant.Grantee) {
+			continue
+		}
+		if column == "" || grant.Columns == nil || containsString(grant.Columns, column) {
+			return true
+		}
+	}
+	return false
+}
+
+// grantable reports whether the user may grant the privilege on the columns
+// of the table, or on the whole table when columns is nil, to others.
+func (privileges *Privileges) grantable(user string, privilege Privilege, table string, columns []string) bool {
+	if privileges.IsOwner(user, table) {
+		return true
+	}
+	roles := privileges.roles(user)
+	for _, grant := range privileges.Grants {
+		if grant.Privilege != privilege || grant.Table != table || !grant.GrantOption || !containsString(roles, grant.Grantee) {
+			continue
+		}
+		if grant.Columns == nil {
+			return true
+		}
+		if ok, _ := ContainsAll(grant.Columns, columns); ok && columns != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// isAdmin reports whether the user may grant the role to others: the user
+// owns the role, or is a member of it with the ADMIN OPTION.
+func (privileges *Privileges) isAdmin(user, role string) bool {
+	roles := privileges.roles(user)
+	if owner, ok := privileges.RoleOwners[role]; ok && containsString(roles, owner) {
+		return true
+	}
+	for _, membership := range privileges.Memberships {
+		if membership.Role == role && membership.AdminOption && containsString(roles, membership.Member) {
+			return true
+		}
+	}
+	return false
+}
+
+// grant records the privilege of the grantee, on the columns or on the
+// whole table when columns is nil.
+func (privileges *Privileges) grant(grantee string, privilege Privilege, table string, columns []string, grantOption bool) {
+	for i := range privileges.Grants {
+		grant := &privileges.Grants[i]
+		if grant.Grantee != grantee || grant.Privilege != privilege || grant.Table != table || (grant.Columns == nil) != (columns == nil) {
+			continue
+		}
+		if columns != nil && grant.GrantOption != grantOption {
+			continue
+		}
+		for _, column := range columns {
+			if !containsString(grant.Columns, column) {
+				grant.Columns = append(grant.Columns, column)
+			}
+		}
+		grant.GrantOption = grant.GrantOption || grantOption
+		return
+	}
+	privileges.Grants = append(privileges.Grants, Grant{Grantee: grantee, Privilege: privilege, Table: table, Columns: columns, GrantOption: grantOption})
+}
+
+// revoke removes the privilege of the grantee on the columns, or on the whole
+// table when columns is nil. Only the grant option is removed when
+// grantOption is set.
+func (privileges *Privileges) revoke(grantee string, privilege Privilege, table string, columns []string, grantOption bool) {
+	grants := privileges.Grants[:0]
+	for _, grant := range privileges.Grants {
+		if grant.Grantee == grantee && grant.Privilege == privilege && grant.Table == table && (grant.Columns == nil) == (columns == nil) {
+			switch {
+			case grantOption:
+				grant.GrantOption = false
+			case columns == nil:
+				continue
+			default:
+				var kept []string
+				for _, column := range grant.Columns {
+					if !containsString(columns, column) {
+						kept = append(kept, column)
+					}
+				}
+				if len(kept) == 0 {
+					continue
+				}
+				grant.Columns = kept
+			}
+		}
+		grants = append(grants, grant)
+	}
+	privileges.Grants = grants
+}
+
+// dropTable forgets the owner of the table and the privileges on it.
+func (privileges *Privileges) dropTable(table string) {
+	delete(privileges.Owners, table)
+	grants := privileges.Grants[:0]
+	for _, grant := range privileges.Grants {
+		if grant.Table != table {
+			grants = append(grants, grant)
+		}
+	}
+	privileges.Grants = grants
+}
+
+// setOwner makes the user the owner of the table.
+func (privileges *Privileges) setOwner(table, user string) {
+	if privileges.Owners == nil {
+		privileges.Owners = map[string]string{}
+	}
+	privileges.Owners[table] = user
+}
+
+// columnReads gathers the columns a statement reads, by table, for the
+// SELECT privilege checks.
+type columnReads map[string]map[string]bool
+
+func (reads columnReads) add(table, column string) {
+	if reads == nil {
+		return
+	}
+	if reads[table] == nil {
+		reads[table] = map[string]bool{}
+	}
+	reads[table][column] = true
+}
+
+// validateGrant checks the tables, columns and privileges of a GRANT or
+// REVOKE and returns the tables, as stored in the schema.
+func (parser *SQLParser) validateGrant(stmt *GrantStmt) ([]string, error) {
+	if len(stmt.Roles) > 0 {
+		return nil, nil
+	}
+	tables, err := parser.validateTableExistence(stmt.Tables)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range stmt.Privileges {
+		if len(item.Columns) == 0 {
+			continue
+		}
+		if item.Privilege != AllPrivileges && !containsPrivilege(columnPrivileges, item.Privilege) {
+			return nil, fmt.Errorf("%s privileges can not be granted on columns", item.Privilege)
+		}
+		if _, err := parser.validateColumnExistence(tables, columnRefs(item.Columns)); err != nil {
+			return nil, err
+		}
+	}
+	return tables, nil
+}
+
+// grantedPrivileges returns the privileges the item stands for, ALL standing
+// for every privilege of the table or of the columns, and the columns they
+// are on, as stored in the schema, nil for the whole table.
+func (parser *SQLParser) grantedPrivileges(item PrivilegeItem, table string) ([]Privilege, []string) {
+	var columns []string
+	for _, column := range item.Columns {
+		name, _ := parser.Schema.LookupColumn(table, column)
+		columns = append(columns, name)
+	}
+	switch {
+	case item.Privilege != AllPrivileges:
+		return []Privilege{item.Privilege}, columns
+	case columns == nil:
+		return tablePrivileges, nil
+	}
+	return columnPrivileges, columns
+}
+
+// applyGrant records the privileges or role memberships granted or revoked
+// by the statement in the privilege model.
+func (parser *SQLParser) applyGrant(stmt *GrantStmt, tables []string) {
+	privileges := parser.Privileges
+	for _, grantee := range identifierNames(stmt.Grantees) {
+		for _, role := range identifierNames(stmt.Roles) {
+			memberships := privileges.Memberships[:0]
+			adminOption := stmt.GrantOption
+			for _, membership := range privileges.Memberships {
+				if membership.Role != role || membership.Member != grantee {
+					memberships = append(memberships, membership)
+					continue
+				}
+				adminOption = adminOption || membership.AdminOption
+			}
+			privileges.Memberships = memberships
+			switch {
+			case !stmt.Revoke:
+				privileges.Memberships = append(privileges.Memberships, Membership{Role: role, Member: grantee, AdminOption: adminOption})
+			case stmt.GrantOption:
+				// REVOKE ADMIN OPTION FOR keeps the membership
+				privileges.Memberships = append(privileges.Memberships, Membership{Role: role, Member: grantee})
+			}
+		}
+		for _, table := range tables {
+			for _, item := range stmt.Privileges {
+				granted, columns := parser.grantedPrivileges(item, table)
+				for _, privilege := range granted {
+					if stmt.Revoke {
+						privileges.revoke(grantee, privilege, table, columns, stmt.GrantOption)
+					} else {
+						privileges.grant(grantee, privilege, table, columns, stmt.GrantOption)
+					}
+				}
+			}
+		}
+	}
+}
+
+// checkPrivileges rejects the analysed statement when the current user does
+// not hold the privileges it requires: SELECT on the columns it reads,
+// INSERT, UPDATE, DELETE or TRUNCATE on the tables and columns it writes,
+// the ownership of the tables and views it drops, replaces or indexes, and
+// the right to grant the privileges and roles of GRANT and REVOKE. EXECUTE
+// requires the privileges of the prepared statement.
+func (parser *SQLParser) checkPrivileges(parsedStmt ParsedStmt, reads columnReads) error {
+	privileges := parser.Privileges
+	user := privileges.User
+
+	switch stmt := parsedStmt.Stmt.(type) {
+	case *SelectStmt:
+		// a table none of whose columns are read, as in SELECT COUNT(*),
+		// still requires the privilege on one of its columns
+		for _, table := range parsedStmt.Tables {
+			if reads[table] == nil && !privileges.Allowed(user, SelectPrivilege, table, "") {
+				return fmt.Errorf("permission denied: user %s can not SELECT from table %s", user, table)
+			}
+		}
+	case *InsertStmt:
+		for _, column := range parsedStmt.Columns {
+			if !privileges.Allowed(user, InsertPrivilege, parsedStmt.Tables[0], column) {
+				return fmt.Errorf("permission denied: user %s can not INSERT into column %s of table %s", user, column, parsedStmt.Tables[0])
+			}
+		}
+		// the conflicting row is updated, the columns its SET values and
+		// WHERE condition read being gathered in reads
+		if stmt.OnConflict != nil {
+			if err := parser.checkUpdatePrivileges(stmt.OnConflict.Set, parsedStmt.Tables[:1]); err != nil {
+				return err
+			}
+		}
+	case *UpdateStmt:
+		if err := parser.checkUpdatePrivileges(stmt.Set, parsedStmt.Tables[:1+len(stmt.Joined)]); err != nil {
+			return err
+		}
+	case *DeleteStmt:
+		if !privileges.Allowed(user, DeletePrivilege, parsedStmt.Tables[0], "") {
+			return fmt.Errorf("permission denied: user %s can not DELETE from table %s", user, parsedStmt.Tables[0])
+		}
+	case *TruncateStmt:
+		for _, table := range parsedStmt.Tables {
+			if !privileges.Allowed(user, TruncatePrivilege, table, "") {
+				return fmt.Errorf("permission denied: user %s can not TRUNCATE table %s", user, table)
+			}
+		}
+	case *Drop:
+		// DROP INDEX requires the ownership of the table of the index
+		for _, table := range parsedStmt.Tables {
+			if !privileges.IsOwner(user, table) {
+				return fmt.Errorf("permission denied: user %s does not own %s", user, table)
+			}
+		}
+	case *DropSchema:
+		// CASCADE drops the tables and views of the schema, all of which
+		// the user must own
+		for _, table := range parsedStmt.Tables {
+			if !privileges.IsOwner(user, table) {
+				return fmt.Errorf("permission denied: user %s does not own %s", user, table)
+			}
+		}
+	case *Create:
+		if table := parsedStmt.Tables[0]; !stmt.Index.IsEmpty() && !privileges.IsOwner(user, table) {
+			return fmt.Errorf("permission denied: user %s does not own %s", user, table)
+		}
+	case *CreateView:
+		if view := parsedStmt.Tables[0]; parser.Schema.IsView(view) && !privileges.IsOwner(user, view) {
+			return fmt.Errorf("permission denied: user %s does not own %s", user, view)
+		}
+	case *ExecuteStmt:
+		// the prepared statement runs with the privileges of the user
+		// executing it, not of the one who prepared it
+		prepared, _ := parser.Prepared.Lookup(stmt.Name.Name)
+		executed := columnReads{}
+		analysed, err := parser.analyzeStatement(*flattenStatement(prepared.Stmt.Stmt), executed)
+		if err != nil {
+			return err
+		}
+		if err := parser.checkPrivileges(analysed, executed); err != nil {
+			return err
+		}
+	case *GrantStmt:
+		for _, role := range identifierNames(stmt.Roles) {
+			if !privileges.isAdmin(user, role) {
+				return fmt.Errorf("permission denied: user %s can not grant role %s", user, role)
+			}
+		}
+		for _, table := range parsedStmt.Tables {
+			for _, item := range stmt.Privileges {
+				granted, columns := parser.grantedPrivileges(item, table)
+				for _, privilege := range granted {
+					if !privileges.grantable(user, privilege, table, columns) {
+						return fmt.Errorf("permission denied: user %s can not grant %s on table %s", user, privilege, table)
+					}
+				}
+			}
+		}
+	}
+
+	for _, table := range parsedStmt.Tables {
+		columns := make([]string, 0, len(reads[table]))
+		for column := range reads[table] {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		for _, column := range columns {
+			if !privileges.Allowed(user, SelectPrivilege, table, column) {
+				return fmt.Errorf("permission denied: user %s can not SELECT column %s of table %s", user, column, table)
+			}
+		}
+	}
+	return nil
+}
+
+// checkUpdatePrivileges rejects the assignments of the columns of the
+// updated tables the current user can not UPDATE.
+func (parser *SQLParser) checkUpdatePrivileges(set []Assignment, updated []string) error {
+	privileges := parser.Privileges
+	for _, assignment := range set {
+		for _, column := range assignment.Columns {
+			table, name, err := parser.resolveColumn(updated, column)
+			if err != nil {
+				return err
+			}
+			if !privileges.Allowed(privileges.User, UpdatePrivilege, table, name) {
+				return fmt.Errorf("permission denied: user %s can not UPDATE column %s of table %s", privileges.User, name, table)
+			}
+		}
+	}
+	return nil
+}
+
+func containsPrivilege(privileges []Privilege, privilege Privilege) bool {
+	for _, p := range privileges {
+		if p == privilege {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testPrivileges returns a privilege model on the test schema: ALICE owns
+// the tables and the role ANALYSTS, BOB holds some privileges on USERS,
+// CAROL may grant SELECT on ORDERS and DAVE reads ORDERS through ANALYSTS.
+func testPrivileges(user string) *Privileges {
+	return &Privileges{
+		User:       user,
+		Owners:     map[string]string{"USERS": "ALICE", "ORDERS": "ALICE"},
+		RoleOwners: map[string]string{"ANALYSTS": "ALICE"},
+		Grants: []Grant{
+			{Grantee: "BOB", Privilege: SelectPrivilege, Table: "USERS", Columns: []string{"ID", "NAME"}},
+			{Grantee: "BOB", Privilege: InsertPrivilege, Table: "USERS"},
+			{Grantee: "BOB", Privilege: UpdatePrivilege, Table: "USERS", Columns: []string{"NAME"}},
+			{Grantee: "CAROL", Privilege: SelectPrivilege, Table: "ORDERS", GrantOption: true},
+			{Grantee: "ANALYSTS", Privilege: SelectPrivilege, Table: "ORDERS"},
+		},
+		Memberships: []Membership{{Role: "ANALYSTS", Member: "DAVE"}},
+	}
+}
+
+// newUserParser returns a function making parsers on the test schema and
+// privileges, for the statements of the user.
+func newUserParser(user string) func() *SQLParser {
+	return func() *SQLParser {
+		parser := newTestParser()
+		parser.Privileges = testPrivileges(user)
+		return parser
+	}
+}
+
+func TestGrant(t *testing.T) {
+	runParseTests(t, newUserParser("ALICE"), []parseTest{
+		{name: "table privileges", sql: "GRANT SELECT, INSERT ON users TO bob, carol"},
+		{name: "table keyword", sql: "GRANT DELETE ON TABLE users, orders TO bob WITH GRANT OPTION"},
+		{name: "all", sql: "GRANT ALL PRIVILEGES ON orders TO PUBLIC"},
+		{name: "column privileges", sql: "GRANT SELECT (id, name), UPDATE (name) ON users TO eve"},
+		{name: "owned role", sql: "GRANT analysts TO eve WITH ADMIN OPTION"},
+		{name: "revoke", sql: "REVOKE SELECT ON orders FROM carol CASCADE"},
+		{name: "revoke grant option", sql: "REVOKE GRANT OPTION FOR SELECT ON orders FROM carol"},
+		{name: "revoke role", sql: "REVOKE analysts FROM dave"},
+		{name: "revoke admin option", sql: "REVOKE ADMIN OPTION FOR analysts FROM dave"},
+		{name: "unknown privilege", sql: "GRANT FLY ON users TO bob", err: "syntax error: FLY is not a privilege"},
+		{name: "role on a table", sql: "GRANT analysts ON users TO bob", err: "syntax error: ANALYSTS is not a privilege"},
+		{name: "privilege without a table", sql: "GRANT SELECT TO bob", err: "syntax error: SELECT is not a role, privileges are granted ON tables"},
+		{name: "role with columns", sql: "GRANT analysts (id) TO bob", err: "syntax error: ANALYSTS is not a role, privileges are granted ON tables"},
+		{name: "nothing granted", sql: "GRANT 42 ON users TO bob", err: "a privilege or role"},
+		{name: "unknown table", sql: "GRANT SELECT ON nope TO bob", err: "table NOPE does not exist"},
+		{name: "unknown column", sql: "GRANT SELECT (nope) ON users TO bob", err: "column NOPE does not exist"},
+		{name: "table privilege on columns", sql: "GRANT TRUNCATE (id) ON users TO bob", err: "TRUNCATE privileges can not be granted on columns"},
+	})
+
+	runParseTests(t, newUserParser("CAROL"), []parseTest{
+		{name: "with grant option", sql: "GRANT SELECT ON orders TO eve"},
+		{name: "without grant option", sql: "GRANT DELETE ON orders TO eve", err: "permission denied: user CAROL can not grant DELETE on table ORDERS"},
+		{name: "all without grant option", sql: "GRANT ALL ON orders TO eve", err: "permission denied: user CAROL can not grant INSERT on table ORDERS"},
+		{name: "role not owned", sql: "GRANT analysts TO eve", err: "permission denied: user CAROL can not grant role ANALYSTS"},
+	})
+}
+
+func TestPrivilegeChecks(t *testing.T) {
+	runParseTests(t, newUserParser("BOB"), []parseTest{
+		{name: "granted columns", sql: "SELECT id, name FROM users WHERE id = 1"},
+		{name: "count of a table with granted columns", sql: "SELECT COUNT(*) FROM users"},
+		{name: "insert", sql: "INSERT INTO users (id, name, age) VALUES (1, 'ada', 36)"},
+		{name: "update of a granted column", sql: "UPDATE users SET name = 'ada' WHERE id = 1"},
+		{name: "upsert of a granted column", sql: "INSERT INTO users (id, name) VALUES (1, 'ada') ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name"},
+		{name: "column not granted", sql: "SELECT age FROM users", err: "permission denied: user BOB can not SELECT column AGE of table USERS"},
+		{name: "column read by the where clause", sql: "SELECT id FROM users WHERE age > 18", err: "permission denied: user BOB can not SELECT column AGE of table USERS"},
+		{name: "star", sql: "SELECT * FROM users", err: "permission denied: user BOB can not SELECT column AGE of table USERS"},
+		{name: "table not granted", sql: "SELECT COUNT(*) FROM orders", err: "permission denied: user BOB can not SELECT from table ORDERS"},
+		{name: "update of a column not granted", sql: "UPDATE users SET age = 1 WHERE id = 1", err: "permission denied: user BOB can not UPDATE column AGE of table USERS"},
+		{name: "update reading a column not granted", sql: "UPDATE users SET name = 'ada' WHERE age > 1", err: "permission denied: user BOB can not SELECT column AGE of table USERS"},
+		{name: "upsert of a column not granted", sql: "INSERT INTO users (id, age) VALUES (1, 2) ON CONFLICT (id) DO UPDATE SET age = EXCLUDED.age", err: "permission denied: user BOB can not UPDATE column AGE of table USERS"},
+		{name: "upsert reading a column not granted", sql: "INSERT INTO users (id, name) VALUES (1, 'ada') ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name WHERE users.age > 1", err: "permission denied: user BOB can not SELECT column AGE of table USERS"},
+		{name: "insert not granted", sql: "INSERT INTO orders (id) VALUES (1)", err: "permission denied: user BOB can not INSERT into column ID of table ORDERS"},
+		{name: "delete", sql: "DELETE FROM users WHERE id = 1", err: "permission denied: user BOB can not DELETE from table USERS"},
+		{name: "truncate", sql: "TRUNCATE users", err: "permission denied: user BOB can not TRUNCATE table USERS"},
+		{name: "drop a table not owned", sql: "DROP TABLE users", err: "permission denied: user BOB does not own USERS"},
+		{name: "index a table not owned", sql: "CREATE INDEX users_name ON users (name)", err: "permission denied: user BOB does not own USERS"},
+		{name: "drop an index of a table not owned", sql: "DROP INDEX users_email", err: "permission denied: user BOB does not own USERS"},
+	})
+
+	runParseTests(t, newUserParser("DAVE"), []parseTest{
+		{name: "through a role", sql: "SELECT total FROM orders"},
+		{name: "not granted to the role", sql: "SELECT name FROM users", err: "permission denied: user DAVE can not SELECT column NAME of table USERS"},
+	})
+
+	runParseTests(t, newUserParser("ALICE"), []parseTest{
+		{name: "owner", sql: "DELETE FROM users"},
+		{name: "owner indexes", sql: "CREATE INDEX users_name ON users (name)"},
+		{name: "owner drops an index", sql: "DROP INDEX users_email"},
+		{name: "owner drops a table", sql: "DROP TABLE orders"},
+	})
+}
+
+// runAs parses the statements as the user, failing the test on an error.
+func runAs(t *testing.T, parser *SQLParser, user string, statements ...string) {
+	t.Helper()
+	parser.Privileges.User = user
+	for _, sql := range statements {
+		mustParse(t, parser, sql)
+	}
+}
+
+// deniedAs checks that the statement of the user is rejected with the error.
+func deniedAs(t *testing.T, parser *SQLParser, user, sql, want string) {
+	t.Helper()
+	parser.Privileges.User = user
+	_, err := parser.ParseSQL(sql)
+	checkError(t, err, want)
+}
+
+func TestGrantAndRevoke(t *testing.T) {
+	parser := newUserParser("ALICE")()
+	runAs(t, parser, "ALICE", "GRANT SELECT (id, total), UPDATE (total) ON orders TO eve")
+	runAs(t, parser, "EVE", "SELECT id, total FROM orders", "UPDATE orders SET total = 0 WHERE id = 1")
+	deniedAs(t, parser, "EVE", "SELECT user_id FROM orders", "permission denied: user EVE can not SELECT column USER_ID of table ORDERS")
+
+	runAs(t, parser, "ALICE", "REVOKE UPDATE (total) ON orders FROM eve")
+	deniedAs(t, parser, "EVE", "UPDATE orders SET total = 0 WHERE id = 1", "permission denied: user EVE can not UPDATE column TOTAL of table ORDERS")
+
+	// a role gives its privileges to its members, the admin option lets
+	// them grant it
+	runAs(t, parser, "ALICE", "GRANT analysts TO eve WITH ADMIN OPTION")
+	runAs(t, parser, "EVE", "SELECT user_id FROM orders", "GRANT analysts TO frank")
+	runAs(t, parser, "FRANK", "SELECT created_at FROM orders")
+	runAs(t, parser, "ALICE", "REVOKE analysts FROM frank")
+	deniedAs(t, parser, "FRANK", "SELECT created_at FROM orders", "permission denied: user FRANK can not SELECT")
+
+	// PUBLIC stands for every user
+	runAs(t, parser, "ALICE", "GRANT SELECT ON users TO PUBLIC")
+	runAs(t, parser, "ZOE", "SELECT * FROM users")
+}
+
+func TestExecutePrivileges(t *testing.T) {
+	parser := newUserParser("ALICE")()
+	runAs(t, parser, "ALICE", "PREPARE totals AS SELECT total FROM orders", "GRANT SELECT ON orders TO eve")
+	runAs(t, parser, "EVE", "EXECUTE totals")
+
+	// the prepared statement is checked for the user executing it
+	deniedAs(t, parser, "BOB", "EXECUTE totals", "permission denied: user BOB can not SELECT column TOTAL of table ORDERS")
+
+	// and with the privileges held when it is executed
+	runAs(t, parser, "EVE", "PREPARE mine AS SELECT id FROM orders WHERE total > $1")
+	runAs(t, parser, "ALICE", "REVOKE SELECT ON orders FROM eve")
+	deniedAs(t, parser, "EVE", "EXECUTE mine (1)", "permission denied: user EVE can not SELECT column ID of table ORDERS")
+}
+
+func TestCreatedTablePrivileges(t *testing.T) {
+	parser := newUserParser("ALICE")()
+	runAs(t, parser, "EVE", "CREATE TABLE notes (id, body)", "INSERT INTO notes (id, body) VALUES (1, 'a')", "CREATE INDEX notes_body ON notes (body)")
+	if owner := parser.Privileges.Owners["NOTES"]; owner != "EVE" {
+		t.Fatalf("expected EVE to own NOTES, got %q", owner)
+	}
+	if want := []string{"ID", "BODY"}; !reflect.DeepEqual(parser.Schema.GetTableColumns("NOTES"), want) {
+		t.Fatalf("expected the columns %v, got %v", want, parser.Schema.GetTableColumns("NOTES"))
+	}
+
+	deniedAs(t, parser, "ALICE", "SELECT body FROM notes", "permission denied: user ALICE can not SELECT column BODY of table NOTES")
+	deniedAs(t, parser, "ALICE", "DROP INDEX notes_body", "permission denied: user ALICE does not own NOTES")
+	deniedAs(t, parser, "ALICE", "DROP TABLE notes", "permission denied: user ALICE does not own NOTES")
+
+	runAs(t, parser, "EVE", "GRANT SELECT ON notes TO alice")
+	runAs(t, parser, "ALICE", "SELECT body FROM notes")
+	runAs(t, parser, "EVE", "DROP TABLE notes")
+	if _, ok := parser.Privileges.Owners["NOTES"]; ok {
+		t.Fatal("expected the owner of a dropped table to be forgotten")
+	}
+	for _, grant := range parser.Privileges.Grants {
+		if grant.Table == "NOTES" {
+			t.Fatalf("expected the grants on a dropped table to be forgotten, got %+v", grant)
+		}
+	}
+}
+
+func TestDropSchemaPrivileges(t *testing.T) {
+	parser := newUserParser("ALICE")()
+	runAs(t, parser, "ALICE", "CREATE SCHEMA sales", "CREATE TABLE sales.t (id)", "GRANT SELECT ON sales.t TO bob")
+	deniedAs(t, parser, "BOB", "DROP TABLE sales.t", "permission denied: user BOB does not own SALES.T")
+	runAs(t, parser, "ALICE", "CREATE VIEW sales.v AS SELECT id FROM sales.t")
+	runAs(t, parser, "BOB", "CREATE TABLE sales.mine (id)")
+
+	// CASCADE drops the tables and views of the schema, all of which the
+	// user must own
+	deniedAs(t, parser, "BOB", "DROP SCHEMA sales CASCADE", "permission denied: user BOB does not own SALES.T")
+	deniedAs(t, parser, "ALICE", "DROP SCHEMA sales CASCADE", "permission denied: user ALICE does not own SALES.MINE")
+
+	runAs(t, parser, "BOB", "DROP TABLE sales.mine")
+	parser.Privileges.User = "ALICE"
+	parsedStmt := mustParse(t, parser, "EXPLAIN DROP SCHEMA sales CASCADE")
+	if want := []string{"SALES.T", "SALES.V"}; !reflect.DeepEqual(parsedStmt.Tables, want) {
+		t.Fatalf("expected the dropped tables %v, got %v", want, parsedStmt.Tables)
+	}
+	runAs(t, parser, "ALICE", "DROP SCHEMA sales CASCADE")
+	for _, table := range []string{"SALES.T", "SALES.V"} {
+		if _, ok := parser.Privileges.Owners[table]; ok {
+			t.Fatalf("expected the owner of %s to be forgotten", table)
+		}
+	}
+	for _, grant := range parser.Privileges.Grants {
+		if grant.Table == "SALES.T" {
+			t.Fatalf("expected the grants on a dropped schema to be forgotten, got %+v", grant)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package sqlParser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ResultColumn describes a column of the result set returned by a
+// statement, by its select list or RETURNING clause.
+type ResultColumn struct {
+	Name   string // the alias, or the name of the column the value is read from
+	Type   DataType
+	Table  string // table and column the value is read from, empty for computed values
+	Column string
+}
+
+// unnamedColumn names the result columns that are neither aliased nor read
+// from a column.
+const unnamedColumn = "?column?"
+
+// resultColumns resolves the items of a select list or RETURNING clause
+// against the scope of the statement and returns the columns of the result
+// set, * standing for every column of the tables in their schema order and
+// table.* for every column of the table.
+func (parser *SQLParser) resultColumns(items []SelectItem, scope exprScope) ([]ResultColumn, error) {
+	var result []ResultColumn
+	for _, item := range items {
+		if star, ok := item.Expr.(*Star); ok {
+			tables, err := parser.starTables(star, scope)
+			if err != nil {
+				return nil, err
+			}
+			for _, table := range tables {
+				for _, name := range parser.Schema.GetTableColumns(table) {
+					scope.reads.add(table, name)
+					result = append(result, ResultColumn{
+						Name:   name,
+						Type:   parser.Schema.columnType(table, name),
+						Table:  table,
+						Column: name,
+					})
+				}
+			}
+			continue
+		}
+
+		column := ResultColumn{Name: unnamedColumn}
+		switch expr := item.Expr.(type) {
+		case *ColumnRef:
+			table, name, err := scope.resolveColumn(parser, expr)
+			if err != nil {
+				return nil, err
+			}
+			column = ResultColumn{Name: name, Table: table, Column: name}
+		case *FuncCall:
+			// function results are named after the function
+			column.Name = expr.Name.Name
+		}
+		typ, err := parser.exprType(item.Expr, scope)
+		if err != nil {
+			return nil, err
+		}
+		column.Type = typ
+		if !item.Alias.IsEmpty() {
+			column.Name = item.Alias.Name
+		}
+		result = append(result, column)
+	}
+	return result, nil
+}
+
+// starTables returns the tables whose columns the star stands for, in the
+// order of the statement: all of them for *, the one named for table.*.
+func (parser *SQLParser) starTables(star *Star, scope exprScope) ([]string, error) {
+	if star.Table.Name.IsEmpty() {
+		return scope.tables, nil
+	}
+	if !star.Table.Schema.IsEmpty() {
+		if _, err := parser.Schema.LookupSchema(star.Table.Schema); err != nil {
+			return nil, err
+		}
+	}
+	for _, table := range scope.tables {
+		if parser.Schema.matchesTable(star.Table.Schema, star.Table.Name, table) {
+			return []string{table}, nil
+		}
+	}
+	return nil, fmt.Errorf("table %s of %s is not part of the statement", star.Table, star)
+}
+
+// validateSelectOrder checks the ORDER BY and DISTINCT ON items of a
+// SELECT, and that the DISTINCT ON expressions are the leading ORDER BY
+// ones, in any order, so that the row kept for each group is well defined.
+func (parser *SQLParser) validateSelectOrder(stmt *SelectStmt, scope exprScope) error {
+	outputs, err := parser.selectOutputs(stmt.Columns, scope)
+	if err != nil {
+		return err
+	}
+	var orderBy, distinctOn []Expr
+	for _, item := range stmt.OrderBy {
+		expr, err := parser.outputExpr("ORDER BY", item.Expr, stmt.Columns, outputs, scope)
+		if err != nil {
+			return err
+		}
+		orderBy = append(orderBy, expr)
+	}
+	for _, item := range stmt.DistinctOn {
+		expr, err := parser.outputExpr("DISTINCT ON", item, stmt.Columns, outputs, scope)
+		if err != nil {
+			return err
+		}
+		distinctOn = append(distinctOn, expr)
+	}
+
+	matched := make([]bool, len(distinctOn))
+	count := 0
+	for _, expr := range orderBy {
+		if count == len(distinctOn) {
+			break
+		}
+		found := false
+		for i, distinct := range distinctOn {
+			if parser.sameExpr(expr, distinct, scope) {
+				found = true
+				if !matched[i] {
+					matched[i] = true
+					count++
+				}
+			}
+		}
+		if !found {
+			return errors.New("SELECT DISTINCT ON expressions must match the leading ORDER BY expressions")
+		}
+	}
+	return nil
+}
+
+// validateGroupBy checks the GROUP BY items of a SELECT and returns the
+// expressions they stand for. Like the ORDER BY items they are positions,
+// aliases or expressions, which can not call aggregate or window functions.
+func (parser *SQLParser) validateGroupBy(stmt *SelectStmt, scope exprScope) ([]Expr, error) {
+	outputs, err := parser.selectOutputs(stmt.Columns, scope)
+	if err != nil {
+		return nil, err
+	}
+	var groupBy []Expr
+	for _, item := range stmt.GroupBy {
+		expr, err := parser.outputExpr("GROUP BY", item, stmt.Columns, outputs, scope)
+		if err != nil {
+			return nil, err
+		}
+		// the select items are checked again, against the GROUP BY scope
+		if expr != item {
+			if _, err := parser.exprType(expr, scope); err != nil {
+				return nil, err
+			}
+		}
+		groupBy = append(groupBy, expr)
+	}
+	return groupBy, nil
+}
+
+// validateGrouping checks a grouped SELECT, one with GROUP BY or aggregate
+// functions: outside of the aggregates, its select list and ORDER BY can
+// only read the columns through the GROUP BY expressions, which have a
+// single value in each group.
+func (parser *SQLParser) validateGrouping(stmt *SelectStmt, groupBy []Expr, scope exprScope) error {
+	outputs, err := parser.selectOutputs(stmt.Columns, scope)
+	if err != nil {
+		return err
+	}
+	exprs := append([]Expr(nil), outputs...)
+	for _, item := range stmt.OrderBy {
+		expr, err := parser.outputExpr("ORDER BY", item.Expr, stmt.Columns, outputs, scope)
+		if err != nil {
+			return err
+		}
+		exprs = append(exprs, expr)
+	}
+
+	grouped := len(groupBy) > 0
+	for _, expr := range exprs {
+		walkExpr(expr, func(expr Expr) bool {
+			grouped = grouped || parser.isAggregate(expr)
+			return !grouped
+		})
+	}
+	if !grouped {
+		return nil
+	}
+
+	for _, expr := range exprs {
+		var ungrouped *ColumnRef
+		walkExpr(expr, func(expr Expr) bool {
+			if ungrouped != nil || parser.isAggregate(expr) {
+				return false
+			}
+			for _, group := range groupBy {
+				if parser.sameExpr(expr, group, scope) {
+					return false
+				}
+			}
+			if column, ok := expr.(*ColumnRef); ok {
+				ungrouped = column
+				return false
+			}
+			return true
+		})
+		if ungrouped != nil {
+			return fmt.Errorf("column %s must appear in the GROUP BY clause or be used in an aggregate function", ungrouped)
+		}
+	}
+	return nil
+}
+
+// isAggregate reports whether the expression is a call of an aggregate
+// function computed over the rows of a group, not over a window.
+func (parser *SQLParser) isAggregate(expr Expr) bool {
+	call, ok := expr.(*FuncCall)
+	if !ok || call.Over != nil {
+		return false
+	}
+	fn, ok := parser.functions().Lookup(call.Name.Name)
+	return ok && fn.Aggregate
+}
+
+// selectOutputs returns the expression of every column of the result of a
+// select list, the columns read through a star being referred to by table
+// and name.
+func (parser *SQLParser) selectOutputs(items []SelectItem, scope exprScope) ([]Expr, error) {
+	var outputs []Expr
+	for _, item := range items {
+		star, ok := item.Expr.(*Star)
+		if !ok {
+			outputs = append(outputs, item.Expr)
+			continue
+		}
+		tables, err := parser.starTables(star, scope)
+		if err != nil {
+			return nil, err
+		}
+		for _, table := range tables {
+			for _, name := range parser.Schema.GetTableColumns(table) {
+				outputs = append(outputs, parser.Schema.columnRef(table, name))
+			}
+		}
+	}
+	return outputs, nil
+}
+
+// outputExpr returns the expression an ORDER BY or DISTINCT ON item stands
+// for: the column of the result at a position, starting at 1, the select
+// item of an alias, or else the item itself checked against the scope.
+func (parser *SQLParser) outputExpr(clause string, expr Expr, items []SelectItem, outputs []Expr, scope exprScope) (Expr, error) {
+	if literal, ok := expr.(*Literal); ok {
+		if position, ok := literal.Value.(int64); ok {
+			if position < 1 || position > int64(len(outputs)) {
+				return nil, fmt.Errorf("%s position %d is not in select list", clause, position)
+			}
+			return outputs[position-1], nil
+		}
+	}
+	if column, ok := expr.(*ColumnRef); ok && column.Table.IsEmpty() {
+		var aliased []Expr
+		for _, item := range items {
+			if !item.Alias.IsEmpty() && column.Column.Matches(item.Alias.Name) {
+				aliased = append(aliased, item.Expr)
+			}
+		}
+		if len(aliased) > 1 {
+			return nil, fmt.Errorf("%s %s is ambiguous", clause, column)
+		}
+		if len(aliased) == 1 {
+			return aliased[0], nil
+		}
+	}
+	if _, err := parser.exprType(expr, scope); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+// sameExpr reports whether two expressions of a statement are the same, the
+// columns being compared once resolved.
+func (parser *SQLParser) sameExpr(a, b Expr, scope exprScope) bool {
+	left, ok := a.(*ColumnRef)
+	right, ok2 := b.(*ColumnRef)
+	if ok && ok2 {
+		leftTable, leftName, err := scope.resolveColumn(parser, left)
+		if err != nil {
+			return false
+		}
+		rightTable, rightName, err := scope.resolveColumn(parser, right)
+		return err == nil && leftTable == rightTable && leftName == rightName
+	}
+	return a.String() == b.String()
+}
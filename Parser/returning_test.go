@@ -0,0 +1,63 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReturning(t *testing.T) {
+	tests := []struct {
+		name   string
+		sql    string
+		result []ResultColumn
+		err    string
+	}{
+		{
+			name:   "insert returning a column",
+			sql:    "INSERT INTO users (id, name) VALUES (1, 'ann') RETURNING id",
+			result: []ResultColumn{{Name: "ID", Type: IntegerType, Table: "USERS", Column: "ID"}},
+		},
+		{
+			name: "update returning expressions",
+			sql:  "UPDATE users SET age = age + 1 WHERE id = 1 RETURNING age + 1 AS next, name",
+			result: []ResultColumn{
+				{Name: "NEXT", Type: IntegerType},
+				{Name: "NAME", Type: TextType, Table: "USERS", Column: "NAME"},
+			},
+		},
+		{
+			name: "delete returning every column",
+			sql:  "DELETE FROM orders WHERE id = 1 RETURNING *",
+			result: []ResultColumn{
+				{Name: "ID", Type: IntegerType, Table: "ORDERS", Column: "ID"},
+				{Name: "USER_ID", Type: IntegerType, Table: "ORDERS", Column: "USER_ID"},
+				{Name: "TOTAL", Type: DecimalType, Table: "ORDERS", Column: "TOTAL"},
+				{Name: "CREATED_AT", Type: TimestampType, Table: "ORDERS", Column: "CREATED_AT"},
+			},
+		},
+		{
+			name:   "computed column without alias",
+			sql:    "DELETE FROM users WHERE id = 1 RETURNING 1",
+			result: []ResultColumn{{Name: "?column?", Type: IntegerType}},
+		},
+		{name: "unknown column", sql: "INSERT INTO users (id) VALUES (1) RETURNING nope", err: "column NOPE does not exist in table USERS"},
+		{name: "star of another table", sql: "DELETE FROM users WHERE id = 1 RETURNING orders.*", err: "table ORDERS of ORDERS.* is not part of the statement"},
+		{name: "empty returning", sql: "DELETE FROM users WHERE id = 1 RETURNING", err: "syntax error"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsedStmt, err := newTestParser().ParseSQL(test.sql)
+			checkError(t, err, test.err)
+			if test.err == "" && !reflect.DeepEqual(parsedStmt.Result, test.result) {
+				t.Fatalf("expected the result %+v, got %+v", test.result, parsedStmt.Result)
+			}
+		})
+	}
+}
+
+func TestNoReturning(t *testing.T) {
+	parsedStmt := mustParse(t, newTestParser(), "DELETE FROM users WHERE id = 1")
+	if parsedStmt.Result != nil {
+		t.Fatalf("expected no result without RETURNING, got %+v", parsedStmt.Result)
+	}
+}
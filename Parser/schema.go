@@ -5,6 +5,12 @@ in the semantic analysis of the SQL statement.
 
 package sqlParser
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // const schemaMetaFile = "schema.db"
 
 // Schema represents the database schema information.
@@ -17,7 +23,36 @@ type ISchema interface {
 
 // SampleSchema represents a sample database schema.
 type Schema struct {
-	Tables map[string][]string // Maps table names to column names
+	Name        string                       // Name of the schema, used to resolve schema qualified table names
+	Tables      map[string][]string          // Maps table names to column names
+	ColumnTypes map[string]map[string]string // Maps table names to the SQL type of their columns (e.g. "varchar(255)")
+	Indexes     map[string][]Index           // Maps table names to their indexes, including the primary key
+	Views       map[string]View              // Maps view names to their definition, views are read like tables
+
+	// Schemas maps the names of the other schemas of the database to their
+	// tables, views and indexes. The tables and views of another schema are
+	// named qualified by the schema, as SALES.ORDERS, while the ones of this
+	// schema, the default one, are named alone. The names holding a dot or a
+	// double quote are quoted, as SALES."A.B".
+	Schemas    map[string]*Schema
+	Current    string   // the schema new views are created in and names are first searched in (USE), the default schema when empty
+	SearchPath []string // the schemas names are searched in after the current one (SET search_path)
+}
+
+// View is a stored SELECT whose result can be read like a table.
+type View struct {
+	Name    string
+	Columns []ResultColumn // the columns of the view, named and typed after the result of its query
+	Query   *SelectStmt
+	Tables  []string // the tables and views the query reads
+}
+
+// Index describes an index of a table.
+type Index struct {
+	Name    string
+	Columns []string
+	Primary bool // the index of the PRIMARY KEY, it is unique too
+	Unique  bool
 }
 
 func (Schema *Schema) LoadSchema(SchemaName string) error {
@@ -41,16 +76,438 @@ func (Schema *Schema) GetSchemaTables() []string {
 	return tableNames
 }
 
-// GetTableColumns returns the columns of a given table.
+// GetSchemaViews returns the names of the views of the schema.
+func (schema *Schema) GetSchemaViews() []string {
+	viewNames := make([]string, 0, len(schema.Views))
+	for viewName := range schema.Views {
+		viewNames = append(viewNames, viewName)
+	}
+	return viewNames
+}
+
+// IsView reports whether the name, as stored in the schema, is a view.
+func (schema *Schema) IsView(name string) bool {
+	_, ok := schema.LookupView(name)
+	return ok
+}
+
+// LookupView returns the view of the name, as stored in the schema.
+func (schema *Schema) LookupView(name string) (View, bool) {
+	_, schema, name = schema.namespace(name)
+	view, ok := schema.Views[name]
+	return view, ok
+}
+
+// GetSchemaNames returns the names of the schemas of the database, the
+// default one included when it is named, sorted.
+func (schema *Schema) GetSchemaNames() []string {
+	names := make([]string, 0, len(schema.Schemas)+1)
+	if schema.Name != "" {
+		names = append(names, schema.Name)
+	}
+	for name := range schema.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// namespace returns the name of the schema holding the table or view, named
+// as by LookupTable, the schema and the name of the table in it.
+func (schema *Schema) namespace(tableName string) (string, *Schema, string) {
+	parts, ok := splitQualified(tableName)
+	if !ok || len(parts) > 2 {
+		return schema.Name, schema, tableName
+	}
+	if len(parts) == 1 {
+		return schema.Name, schema, parts[0]
+	}
+	if other := schema.child(parts[0]); other != nil {
+		return parts[0], other, parts[1]
+	}
+	return schema.Name, schema, tableName
+}
+
+// splitQualified splits a name built by qualify into its schema and table
+// parts, unquoting them. It reports false when the name is not well formed.
+func splitQualified(name string) ([]string, bool) {
+	var parts []string
+	for {
+		if !strings.HasPrefix(name, `"`) {
+			part, rest, dotted := strings.Cut(name, ".")
+			if part == "" || strings.Contains(part, `"`) {
+				return nil, false
+			}
+			parts = append(parts, part)
+			if !dotted {
+				return parts, true
+			}
+			name = rest
+			continue
+		}
+		// a quoted part ends at the first quote not doubled
+		var part strings.Builder
+		i := 1
+		for ; i < len(name); i++ {
+			if name[i] != '"' {
+				part.WriteByte(name[i])
+				continue
+			}
+			if i+1 < len(name) && name[i+1] == '"' {
+				part.WriteByte('"')
+				i++
+				continue
+			}
+			break
+		}
+		if i >= len(name) {
+			return nil, false
+		}
+		parts = append(parts, part.String())
+		switch rest := name[i+1:]; {
+		case rest == "":
+			return parts, true
+		case strings.HasPrefix(rest, "."):
+			name = rest[1:]
+		default:
+			return nil, false
+		}
+	}
+}
+
+// child returns the schema of the given name, nil when it does not exist.
+func (schema *Schema) child(name string) *Schema {
+	if name == schema.Name {
+		return schema
+	}
+	return schema.Schemas[name]
+}
+
+// qualify names a table of the given schema: alone in the default schema,
+// qualified by the schema in the others. The names holding a dot or a
+// double quote are quoted, so that namespace splits them back unchanged.
+func (schema *Schema) qualify(schemaName, tableName string) string {
+	if schemaName == schema.Name {
+		return qualifiedPart(tableName)
+	}
+	return qualifiedPart(schemaName) + "." + qualifiedPart(tableName)
+}
+
+// qualifiedPart returns the name as a part of a qualified name, quoted when
+// it holds a dot or a double quote.
+func qualifiedPart(name string) string {
+	if strings.ContainsAny(name, `."`) {
+		return Identifier{Name: name, Quoted: true}.String()
+	}
+	return name
+}
+
+// creationSchema returns the schema a new table or view is created in: the
+// schema its name is qualified with, or the current one.
+func (schema *Schema) creationSchema(table TableName) (string, *Schema, error) {
+	schemaName := schema.CurrentSchema()
+	if !table.Schema.IsEmpty() {
+		var err error
+		schemaName, err = schema.LookupSchema(table.Schema)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	namespace := schema.child(schemaName)
+	if namespace == nil {
+		return "", nil, fmt.Errorf("schema %s does not exist", schemaName)
+	}
+	return schemaName, namespace, nil
+}
+
+// CurrentSchema returns the name of the schema new views are created in.
+func (schema *Schema) CurrentSchema() string {
+	if schema.Current == "" {
+		return schema.Name
+	}
+	return schema.Current
+}
+
+// searchPath returns the schemas unqualified names are searched in, in
+// order: the current schema, then the search path.
+func (schema *Schema) searchPath() []string {
+	path := []string{schema.CurrentSchema()}
+	for _, name := range schema.SearchPath {
+		if !containsString(path, name) {
+			path = append(path, name)
+		}
+	}
+	return path
+}
+
+// LookupSchema resolves a schema name of a statement and returns the name
+// the schema is stored under.
+func (schema *Schema) LookupSchema(name Identifier) (string, error) {
+	if resolved, ok := name.resolve(schema.GetSchemaNames()); ok {
+		return resolved, nil
+	}
+	return "", fmt.Errorf("schema %s does not exist", name)
+}
+
+// searched returns the schemas a table name is searched in: the schema it
+// is qualified with, or the search path for an unqualified name.
+func (schema *Schema) searched(qualifier Identifier) ([]string, error) {
+	if qualifier.IsEmpty() {
+		return schema.searchPath(), nil
+	}
+	name, err := schema.LookupSchema(qualifier)
+	if err != nil {
+		return nil, err
+	}
+	return []string{name}, nil
+}
+
+// matchesTable reports whether the name, optionally qualified by a schema,
+// designates the table named as by LookupTable.
+func (schema *Schema) matchesTable(qualifier, name Identifier, tableName string) bool {
+	schemaName, _, local := schema.namespace(tableName)
+	return name.Matches(local) && (qualifier.IsEmpty() || qualifier.Matches(schemaName))
+}
+
+// columnRef returns a reference to the column of the table, named as by
+// LookupTable, qualified by its schema when it is not the default one.
+func (schema *Schema) columnRef(tableName, column string) *ColumnRef {
+	ref := &ColumnRef{Column: Identifier{Name: column, Quoted: true}}
+	schemaName, _, local := schema.namespace(tableName)
+	ref.Table = Identifier{Name: local, Quoted: true}
+	if schemaName != schema.Name {
+		ref.Schema = Identifier{Name: schemaName, Quoted: true}
+	}
+	return ref
+}
+
+// GetTableColumns returns the columns of a given table or view.
 func (schema *Schema) GetTableColumns(tableName string) []string {
+	_, schema, tableName = schema.namespace(tableName)
 	if columns, ok := schema.Tables[tableName]; ok {
 		return columns
 	}
+	if view, ok := schema.Views[tableName]; ok {
+		columns := make([]string, 0, len(view.Columns))
+		for _, column := range view.Columns {
+			columns = append(columns, column.Name)
+		}
+		return columns
+	}
 	return nil
 }
 
-// GetColumnDataType returns the data type of a column in a given table.
+// LookupTable resolves a table or view name of a statement and returns the
+// name the table is stored under in the schema, qualified by its schema
+// when it is not the default one. Unquoted names match ignoring case,
+// quoted names must match exactly. Unqualified names are searched in the
+// current schema, then in the schemas of the search path.
+func (schema *Schema) LookupTable(table TableName) (string, error) {
+	schemaNames, err := schema.searched(table.Schema)
+	if err != nil {
+		return "", err
+	}
+	for _, schemaName := range schemaNames {
+		namespace := schema.child(schemaName)
+		if namespace == nil {
+			continue
+		}
+		if name, ok := table.Name.resolve(namespace.GetSchemaTables()); ok {
+			return schema.qualify(schemaName, name), nil
+		}
+		if name, ok := table.Name.resolve(namespace.GetSchemaViews()); ok {
+			return schema.qualify(schemaName, name), nil
+		}
+	}
+	return "", fmt.Errorf("table %s does not exist in the schema", table.Name)
+}
+
+// LookupColumn resolves a column of a table, named as stored in the schema,
+// and returns the name the column is stored under.
+func (schema *Schema) LookupColumn(tableName string, column Identifier) (string, bool) {
+	return column.resolve(schema.GetTableColumns(tableName))
+}
+
+// GetColumnDataType returns the data type of a column in a given table or
+// view, an empty string when the schema does not record it.
 func (schema *Schema) GetColumnDataType(tableName, columnName string) string {
-	// Implementation omitted for brevity, you would typically retrieve data type from the schema
-	return "varchar(255)" // Sample return type
+	_, schema, tableName = schema.namespace(tableName)
+	if view, ok := schema.Views[tableName]; ok {
+		for _, column := range view.Columns {
+			if column.Name == columnName {
+				return string(column.Type)
+			}
+		}
+	}
+	return schema.ColumnTypes[tableName][columnName]
+}
+
+// putView stores the view in its schema, replacing the view of the same
+// name.
+func (schema *Schema) putView(view View) {
+	_, schema, name := schema.namespace(view.Name)
+	if schema.Views == nil {
+		schema.Views = map[string]View{}
+	}
+	schema.Views[name] = view
+}
+
+// dropView removes the view from its schema.
+func (schema *Schema) dropView(name string) {
+	_, schema, name = schema.namespace(name)
+	delete(schema.Views, name)
+}
+
+// putTable stores the table and its columns in its schema.
+func (schema *Schema) putTable(name string, columns []string) {
+	_, schema, name = schema.namespace(name)
+	if schema.Tables == nil {
+		schema.Tables = map[string][]string{}
+	}
+	schema.Tables[name] = columns
+}
+
+// dropTable removes the table, the types of its columns and its indexes
+// from its schema.
+func (schema *Schema) dropTable(name string) {
+	_, schema, name = schema.namespace(name)
+	delete(schema.Tables, name)
+	delete(schema.ColumnTypes, name)
+	delete(schema.Indexes, name)
+}
+
+// putIndex adds the index to the indexes of the table.
+func (schema *Schema) putIndex(tableName string, index Index) {
+	_, schema, tableName = schema.namespace(tableName)
+	if schema.Indexes == nil {
+		schema.Indexes = map[string][]Index{}
+	}
+	schema.Indexes[tableName] = append(schema.Indexes[tableName], index)
+}
+
+// dropIndex removes the index of the given name from the indexes of the
+// table.
+func (schema *Schema) dropIndex(tableName, name string) {
+	_, schema, tableName = schema.namespace(tableName)
+	var indexes []Index
+	for _, index := range schema.Indexes[tableName] {
+		if index.Name != name {
+			indexes = append(indexes, index)
+		}
+	}
+	schema.Indexes[tableName] = indexes
+}
+
+// indexNames maps the names of the indexes of the schema to their table.
+func (schema *Schema) indexNames() map[string]string {
+	names := map[string]string{}
+	for table, indexes := range schema.Indexes {
+		for _, index := range indexes {
+			names[index.Name] = table
+		}
+	}
+	return names
+}
+
+// lookupIndex resolves an index name of a statement, searched in the
+// current schema then in the search path, and returns the table of the
+// index, named as by LookupTable, and the name the index is stored under.
+func (schema *Schema) lookupIndex(name Identifier) (string, string, error) {
+	for _, schemaName := range schema.searchPath() {
+		namespace := schema.child(schemaName)
+		if namespace == nil {
+			continue
+		}
+		tables := namespace.indexNames()
+		names := make([]string, 0, len(tables))
+		for index := range tables {
+			names = append(names, index)
+		}
+		if index, ok := name.resolve(names); ok {
+			return schema.qualify(schemaName, tables[index]), index, nil
+		}
+	}
+	return "", "", fmt.Errorf("index %s does not exist in the schema", name)
+}
+
+// clone returns a copy of the schema sharing none of its maps and slices
+// with it, so that the statements changing the catalog leave the original
+// as it is.
+func (schema Schema) clone() Schema {
+	copied := schema
+	copied.Tables = make(map[string][]string, len(schema.Tables))
+	for table, columns := range schema.Tables {
+		copied.Tables[table] = append([]string(nil), columns...)
+	}
+	copied.ColumnTypes = make(map[string]map[string]string, len(schema.ColumnTypes))
+	for table, types := range schema.ColumnTypes {
+		copied.ColumnTypes[table] = make(map[string]string, len(types))
+		for column, typ := range types {
+			copied.ColumnTypes[table][column] = typ
+		}
+	}
+	copied.Indexes = make(map[string][]Index, len(schema.Indexes))
+	for table, indexes := range schema.Indexes {
+		for _, index := range indexes {
+			index.Columns = append([]string(nil), index.Columns...)
+			copied.Indexes[table] = append(copied.Indexes[table], index)
+		}
+	}
+	copied.Views = make(map[string]View, len(schema.Views))
+	for name, view := range schema.Views {
+		view.Columns = append([]ResultColumn(nil), view.Columns...)
+		view.Tables = append([]string(nil), view.Tables...)
+		copied.Views[name] = view
+	}
+	if schema.Schemas != nil {
+		copied.Schemas = make(map[string]*Schema, len(schema.Schemas))
+		for name, other := range schema.Schemas {
+			if other != nil {
+				clone := other.clone()
+				other = &clone
+			}
+			copied.Schemas[name] = other
+		}
+	}
+	copied.SearchPath = append([]string(nil), schema.SearchPath...)
+	return copied
+}
+
+// views returns the views of every schema.
+func (schema *Schema) views() []View {
+	var views []View
+	for _, view := range schema.Views {
+		views = append(views, view)
+	}
+	for _, other := range schema.Schemas {
+		for _, view := range other.Views {
+			views = append(views, view)
+		}
+	}
+	return views
+}
+
+// columnType returns the type family of a column.
+func (schema *Schema) columnType(tableName, columnName string) DataType {
+	return ParseDataType(schema.GetColumnDataType(tableName, columnName))
+}
+
+// GetTableIndexes returns the indexes of a given table.
+func (schema *Schema) GetTableIndexes(tableName string) []Index {
+	_, schema, tableName = schema.namespace(tableName)
+	return schema.Indexes[tableName]
+}
+
+// uniqueIndex returns the PRIMARY KEY or UNIQUE index of the table covering
+// exactly the columns, in any order.
+func (schema *Schema) uniqueIndex(tableName string, columns []string) (Index, bool) {
+	for _, index := range schema.GetTableIndexes(tableName) {
+		if !index.Primary && !index.Unique || len(index.Columns) != len(columns) {
+			continue
+		}
+		if valid, _ := ContainsAll(index.Columns, columns); valid {
+			return index, true
+		}
+	}
+	return Index{}, false
 }
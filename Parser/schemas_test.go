@@ -0,0 +1,142 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newSchemasParser returns a parser on the test schema with a second
+// schema, SALES, holding its own ORDERS table and a CUSTOMERS table.
+func newSchemasParser() *SQLParser {
+	schema := testSchema()
+	schema.Schemas = map[string]*Schema{
+		"SALES": {
+			Name:   "SALES",
+			Tables: map[string][]string{"ORDERS": {"ID", "AMOUNT"}, "CUSTOMERS": {"ID", "NAME"}},
+			ColumnTypes: map[string]map[string]string{
+				"ORDERS":    {"ID": "bigint", "AMOUNT": "decimal(10,2)"},
+				"CUSTOMERS": {"ID": "bigint", "NAME": "text"},
+			},
+		},
+	}
+	return NewSQLParser(schema)
+}
+
+func TestSchemas(t *testing.T) {
+	runParseTests(t, newSchemasParser, []parseTest{
+		{name: "create schema", sql: "CREATE SCHEMA hr"},
+		{name: "create database", sql: "CREATE DATABASE hr"},
+		{name: "create existing schema if not exists", sql: "CREATE SCHEMA IF NOT EXISTS sales"},
+		{name: "drop empty schema", sql: "DROP SCHEMA IF EXISTS nope"},
+		{name: "drop schema cascade", sql: "DROP DATABASE sales CASCADE"},
+		{name: "use", sql: "USE sales"},
+		{name: "search path", sql: "SET search_path TO sales, public"},
+		{name: "session search path", sql: "SET SESSION search_path = sales"},
+		{name: "default search path", sql: "SET search_path TO DEFAULT"},
+		{name: "qualified table", sql: "SELECT id, amount FROM sales.orders WHERE amount > 10"},
+		{name: "qualified columns", sql: "SELECT sales.orders.amount, orders.id FROM sales.orders"},
+		{name: "table of the default schema", sql: "SELECT total FROM orders"},
+		{name: "qualified default schema", sql: "SELECT total FROM public.orders"},
+		{name: "insert into another schema", sql: "INSERT INTO sales.orders (id, amount) VALUES (1, 2)"},
+		{name: "table in another schema", sql: "CREATE TABLE sales.notes (id)"},
+		{name: "view in another schema", sql: "CREATE VIEW sales.big AS SELECT id FROM sales.orders WHERE amount > 100"},
+		{name: "existing schema", sql: "CREATE SCHEMA sales", err: "schema SALES already exists"},
+		{name: "drop unknown schema", sql: "DROP SCHEMA nope", err: "schema NOPE does not exist"},
+		{name: "drop default schema", sql: "DROP SCHEMA public CASCADE", err: "can not drop schema PUBLIC, it is the default schema"},
+		{name: "drop schema not empty", sql: "DROP SCHEMA sales", err: "can not drop schema SALES because it is not empty"},
+		{name: "use unknown schema", sql: "USE nope", err: "schema NOPE does not exist"},
+		{name: "unknown setting", sql: "SET nope TO sales", err: "setting NOPE does not exist"},
+		{name: "unknown schema in the search path", sql: "SET search_path TO sales, nope", err: "schema NOPE does not exist"},
+		{name: "schema twice in the search path", sql: "SET search_path TO sales, SALES", err: "schema SALES is specified more than once in the search path"},
+		{name: "unknown schema of a table", sql: "SELECT id FROM nope.orders", err: "schema NOPE does not exist"},
+		{name: "unknown table of a schema", sql: "SELECT id FROM sales.nope", err: "table NOPE does not exist in the schema"},
+		{name: "table of another schema not searched", sql: "SELECT name FROM customers", err: "table CUSTOMERS does not exist in the schema"},
+		{name: "column of the other orders table", sql: "SELECT amount FROM orders", err: "column AMOUNT does not exist"},
+		{name: "existing table of another schema", sql: "CREATE TABLE sales.orders (id)", err: "table SALES.ORDERS already exists"},
+	})
+}
+
+func TestQualifiedStar(t *testing.T) {
+	for _, sql := range []string{
+		"SELECT * FROM sales.orders",
+		"SELECT orders.* FROM sales.orders",
+		"SELECT sales.orders.* FROM sales.orders",
+	} {
+		parsedStmt := mustParse(t, newSchemasParser(), sql)
+		if want := []string{"SALES.ORDERS"}; !reflect.DeepEqual(parsedStmt.Tables, want) {
+			t.Fatalf("%s: expected the tables %v, got %v", sql, want, parsedStmt.Tables)
+		}
+		if want := []string{"ID", "AMOUNT"}; !reflect.DeepEqual(parsedStmt.Columns, want) {
+			t.Fatalf("%s: expected the columns %v, got %v", sql, want, parsedStmt.Columns)
+		}
+	}
+}
+
+func TestCurrentSchema(t *testing.T) {
+	parser := newSchemasParser()
+
+	// USE makes a schema the one names are first searched in and new
+	// tables and views are created in
+	mustParse(t, parser, "USE sales")
+	parsedStmt := mustParse(t, parser, "SELECT amount FROM orders")
+	if want := []string{"SALES.ORDERS"}; !reflect.DeepEqual(parsedStmt.Tables, want) {
+		t.Fatalf("expected the tables %v, got %v", want, parsedStmt.Tables)
+	}
+	mustParse(t, parser, "CREATE VIEW big AS SELECT id FROM orders WHERE amount > 100")
+	mustParse(t, parser, "SELECT id FROM sales.big")
+	mustParse(t, parser, "USE public")
+	_, err := parser.ParseSQL("SELECT id FROM big")
+	checkError(t, err, "table BIG does not exist in the schema")
+
+	// the first schema of the search path is the current one, the others
+	// are searched after it
+	mustParse(t, parser, "SET search_path TO public, sales")
+	mustParse(t, parser, "SELECT name FROM customers")
+	if parsedStmt := mustParse(t, parser, "SELECT total FROM orders"); parsedStmt.Tables[0] != "ORDERS" {
+		t.Fatalf("expected the current schema to be searched first, got %v", parsedStmt.Tables)
+	}
+	mustParse(t, parser, "SET search_path TO sales, public")
+	if parsedStmt := mustParse(t, parser, "SELECT amount FROM orders"); parsedStmt.Tables[0] != "SALES.ORDERS" {
+		t.Fatalf("expected the first schema of the search path to be searched first, got %v", parsedStmt.Tables)
+	}
+	mustParse(t, parser, "SET search_path TO DEFAULT")
+	_, err = parser.ParseSQL("SELECT name FROM customers")
+	checkError(t, err, "table CUSTOMERS does not exist in the schema")
+
+	// a schema can not be dropped while a view of another schema reads it
+	mustParse(t, parser, "CREATE SCHEMA hr")
+	mustParse(t, parser, "CREATE VIEW hr.customers AS SELECT id, name FROM sales.customers")
+	_, err = parser.ParseSQL("DROP SCHEMA sales CASCADE")
+	checkError(t, err, "can not drop schema SALES because view HR.CUSTOMERS depends on it")
+	mustParse(t, parser, "DROP SCHEMA hr CASCADE")
+	mustParse(t, parser, "DROP SCHEMA sales CASCADE")
+	_, err = parser.ParseSQL("SELECT id FROM sales.orders")
+	checkError(t, err, "schema SALES does not exist")
+}
+
+func TestQualifiedNamesWithDots(t *testing.T) {
+	parser := newSchemasParser()
+	mustParse(t, parser, `CREATE TABLE "SALES.X" (id)`)
+	mustParse(t, parser, `CREATE TABLE sales."A.B" (id)`)
+	mustParse(t, parser, `CREATE SCHEMA "SALES.EU"`)
+	mustParse(t, parser, `CREATE TABLE "SALES.EU".t (id)`)
+
+	// a dot in a quoted name never makes it qualified by a schema
+	for sql, want := range map[string]string{
+		`SELECT id FROM "SALES.X"`:    `"SALES.X"`,
+		`SELECT id FROM sales."A.B"`:  `SALES."A.B"`,
+		`SELECT id FROM "SALES.EU".t`: `"SALES.EU".T`,
+	} {
+		parsedStmt := mustParse(t, parser, sql)
+		if !reflect.DeepEqual(parsedStmt.Tables, []string{want}) {
+			t.Fatalf("%s: expected the tables [%s], got %v", sql, want, parsedStmt.Tables)
+		}
+		if columns := parser.Schema.GetTableColumns(want); !reflect.DeepEqual(columns, []string{"ID"}) {
+			t.Fatalf("%s: expected the columns [ID], got %v", sql, columns)
+		}
+	}
+	_, err := parser.ParseSQL("SELECT id FROM sales.x")
+	checkError(t, err, "table X does not exist in the schema")
+	_, err = parser.ParseSQL(`SELECT id FROM sales."EU.T"`)
+	checkError(t, err, `table "EU.T" does not exist in the schema`)
+}
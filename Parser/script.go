@@ -0,0 +1,337 @@
+package sqlParser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// ScriptIterator walks over the statements of a SQL script one at a time.
+// Only the statement currently being parsed is kept in memory, so large dump
+// files can be processed without loading them completely.
+//
+//	it := parser.ParseScript(file)
+//	for it.Next() {
+//		stmt := it.Statement()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle error
+//	}
+type ScriptIterator struct {
+	parser *SQLParser
+	reader *bufio.Reader
+	line   int // current line of the reader, used in error messages
+	start  int // line the current statement starts on
+	count  int // number of statements read so far
+	text   string
+	stmt   ParsedStmt
+	err    error
+	done   bool
+
+	transactions TransactionChecker // checks the transaction control statements when parser.CheckTransactions is set
+}
+
+// ParseScript returns an iterator over the statements read from r.
+// Statements are split on semicolons that appear outside of string literals,
+// quoted identifiers, comments and dollar-quoted bodies.
+func (parser *SQLParser) ParseScript(r io.Reader) *ScriptIterator {
+	return &ScriptIterator{
+		parser: parser,
+		reader: bufio.NewReader(r),
+		line:   1,
+	}
+}
+
+// Next reads and parses the next statement of the script. It returns false
+// when the script is exhausted or when an error occurred, Err tells which.
+func (it *ScriptIterator) Next() bool {
+	if it.done {
+		return it.end()
+	}
+
+	for {
+		text, hasContent, err := it.scanStatement()
+		if err != nil {
+			it.fail(err)
+			return false
+		}
+		if !hasContent {
+			// empty statement (";;") or trailing comments after the last one
+			if it.done {
+				return it.end()
+			}
+			continue
+		}
+
+		it.count++
+		stmt, err := it.parser.ParseSQL(text)
+		if err == nil && it.parser.CheckTransactions {
+			err = it.transactions.Check(stmt.Stmt)
+		}
+		if err != nil {
+			it.fail(fmt.Errorf("statement %d (line %d): %w", it.count, it.start, err))
+			return false
+		}
+		it.text = text
+		it.stmt = stmt
+		return true
+	}
+}
+
+// Statement returns the statement parsed by the last call to Next.
+func (it *ScriptIterator) Statement() ParsedStmt {
+	return it.stmt
+}
+
+// Text returns the source text of the statement parsed by the last call to Next.
+func (it *ScriptIterator) Text() string {
+	return it.text
+}
+
+// Err returns the first error met while reading or parsing the script.
+func (it *ScriptIterator) Err() error {
+	return it.err
+}
+
+// end checks, once the script is exhausted, that it left no transaction
+// open when the transactions are checked. It always returns false.
+func (it *ScriptIterator) end() bool {
+	if it.err == nil && it.parser.CheckTransactions {
+		if err := it.transactions.End(); err != nil {
+			it.fail(err)
+		}
+	}
+	return false
+}
+
+func (it *ScriptIterator) fail(err error) {
+	it.err = err
+	it.done = true
+	it.text = ""
+	it.stmt = ParsedStmt{}
+}
+
+// scanStatement reads runes up to the next top level semicolon and returns
+// the statement text without it. hasContent reports whether the statement
+// holds anything besides whitespace and comments.
+func (it *ScriptIterator) scanStatement() (text string, hasContent bool, err error) {
+	var sb strings.Builder
+	// last two runes of the statement outside of quotes and comments
+	var prev, beforePrev rune
+
+	for {
+		r, err := it.readRune()
+		if err == io.EOF {
+			it.done = true
+			return strings.TrimSpace(sb.String()), hasContent, nil
+		}
+		if err != nil {
+			return "", false, err
+		}
+
+		switch {
+		case r == ';':
+			return strings.TrimSpace(sb.String()), hasContent, nil
+
+		case r == '\'':
+			// E'...' strings allow backslash escapes
+			escapes := it.parser.dialect().BackslashEscapes ||
+				((prev == 'E' || prev == 'e') && !isWordRune(beforePrev))
+			sb.WriteRune(r)
+			if err := it.scanQuoted(&sb, '\'', escapes); err != nil {
+				return "", false, err
+			}
+
+		case r == '"' || r == '`' || strings.ContainsRune(it.parser.dialect().IdentifierQuotes, r):
+			sb.WriteRune(r)
+			if err := it.scanQuoted(&sb, closingQuote(r), false); err != nil {
+				return "", false, err
+			}
+
+		case r == '-' && it.peekRune() == '-':
+			sb.WriteRune(r)
+			if err := it.scanLineComment(&sb); err != nil {
+				return "", false, err
+			}
+			prev, beforePrev = ' ', prev
+			continue
+
+		case r == '/' && it.peekRune() == '*':
+			sb.WriteRune(r)
+			if err := it.scanBlockComment(&sb); err != nil {
+				return "", false, err
+			}
+			prev, beforePrev = ' ', prev
+			continue
+
+		case r == '$' && it.parser.dialect().DollarQuotes && !isWordRune(prev):
+			sb.WriteRune(r)
+			if err := it.scanDollar(&sb); err != nil {
+				return "", false, err
+			}
+
+		default:
+			sb.WriteRune(r)
+		}
+
+		if !hasContent && !unicode.IsSpace(r) {
+			hasContent = true
+			it.start = it.line
+		}
+		prev, beforePrev = r, prev
+	}
+}
+
+// scanQuoted copies a quoted string or identifier up to its closing quote.
+// A doubled quote is read as an escaped quote by simply reopening the
+// literal on the next call.
+func (it *ScriptIterator) scanQuoted(sb *strings.Builder, quote rune, backslashEscapes bool) error {
+	for {
+		r, err := it.readRune()
+		if err == io.EOF {
+			return fmt.Errorf("line %d: unterminated quoted string", it.line)
+		}
+		if err != nil {
+			return err
+		}
+		sb.WriteRune(r)
+
+		if backslashEscapes && r == '\\' {
+			r, err = it.readRune()
+			if err == io.EOF {
+				return fmt.Errorf("line %d: unterminated quoted string", it.line)
+			}
+			if err != nil {
+				return err
+			}
+			sb.WriteRune(r)
+			continue
+		}
+		if r == quote {
+			if it.peekRune() == quote {
+				r, _ = it.readRune()
+				sb.WriteRune(r)
+				continue
+			}
+			return nil
+		}
+	}
+}
+
+func (it *ScriptIterator) scanLineComment(sb *strings.Builder) error {
+	for {
+		r, err := it.readRune()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		sb.WriteRune(r)
+		if r == '\n' {
+			return nil
+		}
+	}
+}
+
+// scanBlockComment copies a /* */ comment, the opening slash has already
+// been consumed. Block comments are nested when the dialect allows it.
+func (it *ScriptIterator) scanBlockComment(sb *strings.Builder) error {
+	r, _ := it.readRune() // the '*' of the opening delimiter
+	sb.WriteRune(r)
+
+	nested := it.parser.dialect().NestedComments
+	depth := 1
+	for depth > 0 {
+		r, err := it.readRune()
+		if err == io.EOF {
+			return fmt.Errorf("line %d: unterminated block comment", it.line)
+		}
+		if err != nil {
+			return err
+		}
+		sb.WriteRune(r)
+
+		switch {
+		case r == '*' && it.peekRune() == '/':
+			r, _ = it.readRune()
+			sb.WriteRune(r)
+			depth--
+		case nested && r == '/' && it.peekRune() == '*':
+			r, _ = it.readRune()
+			sb.WriteRune(r)
+			depth++
+		}
+	}
+	return nil
+}
+
+// scanDollar handles a '$' met outside of quotes. When it opens a
+// dollar-quoted body ($$ or $tag$) the whole body is copied up to the
+// matching closing tag, otherwise (e.g. a $1 parameter) only the runes
+// looked at are copied.
+func (it *ScriptIterator) scanDollar(sb *strings.Builder) error {
+	var tag strings.Builder
+	tag.WriteRune('$')
+	for {
+		r := it.peekRune()
+		if r == '$' {
+			break
+		}
+		if !isWordRune(r) || (tag.Len() == 1 && unicode.IsDigit(r)) {
+			// not a dollar quote, the runes read so far are plain text
+			return nil
+		}
+		r, _ = it.readRune()
+		tag.WriteRune(r)
+		sb.WriteRune(r)
+	}
+	r, _ := it.readRune()
+	tag.WriteRune(r)
+	sb.WriteRune(r)
+
+	closing := tag.String()
+	startLine := it.line
+	bodyStart := sb.Len()
+	for {
+		r, err := it.readRune()
+		if err == io.EOF {
+			return fmt.Errorf("line %d: unterminated dollar-quoted string %s", startLine, closing)
+		}
+		if err != nil {
+			return err
+		}
+		sb.WriteRune(r)
+		if r == '$' && sb.Len()-bodyStart >= len(closing) && strings.HasSuffix(sb.String(), closing) {
+			return nil
+		}
+	}
+}
+
+func (it *ScriptIterator) readRune() (rune, error) {
+	r, _, err := it.reader.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if r == '\n' {
+		it.line++
+	}
+	return r, nil
+}
+
+// peekRune returns the next rune without consuming it, 0 at the end of input.
+func (it *ScriptIterator) peekRune() rune {
+	r, _, err := it.reader.ReadRune()
+	if err != nil {
+		return 0
+	}
+	it.reader.UnreadRune()
+	return r
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,110 @@
+package sqlParser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseScript(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		texts  []string // the statements read, in order
+		err    string
+	}{
+		{
+			name:   "statements on one line",
+			script: "SELECT id FROM users; DELETE FROM orders WHERE id = 1;",
+			texts:  []string{"SELECT id FROM users", "DELETE FROM orders WHERE id = 1"},
+		},
+		{
+			name:   "last statement without semicolon",
+			script: "SELECT id FROM users;\nSELECT name FROM users",
+			texts:  []string{"SELECT id FROM users", "SELECT name FROM users"},
+		},
+		{
+			name:   "empty statements and trailing comments",
+			script: ";;SELECT id FROM users;;\n-- the end\n",
+			texts:  []string{"SELECT id FROM users"},
+		},
+		{
+			name:   "semicolons in strings, identifiers and comments",
+			script: "SELECT id FROM users WHERE name = 'a;b' -- c;d\n; SELECT \"ID\" FROM users /* e;f */;",
+			texts:  []string{"SELECT id FROM users WHERE name = 'a;b' -- c;d", "SELECT \"ID\" FROM users /* e;f */"},
+		},
+		{
+			name:   "semicolon in a dollar-quoted string",
+			script: "SELECT id FROM users WHERE name = $$a;b$$; SELECT id FROM users WHERE name = $x$c;$$;d$x$;",
+			texts:  []string{"SELECT id FROM users WHERE name = $$a;b$$", "SELECT id FROM users WHERE name = $x$c;$$;d$x$"},
+		},
+		{
+			name:   "invalid statement",
+			script: "SELECT id FROM users;\n\nSELECT nope FROM users;",
+			texts:  []string{"SELECT id FROM users"},
+			err:    "statement 2 (line 3): column NOPE does not exist in table USERS",
+		},
+		{
+			name:   "unterminated string",
+			script: "SELECT id FROM users;\nSELECT 'abc",
+			texts:  []string{"SELECT id FROM users"},
+			err:    "line 2: unterminated quoted string",
+		},
+		{
+			name:   "unterminated block comment",
+			script: "SELECT id FROM users /* no end",
+			err:    "unterminated block comment",
+		},
+		{
+			name:   "unterminated dollar-quoted string",
+			script: "SELECT $$abc;",
+			err:    "unterminated dollar-quoted string $$",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			it := newTestParser().ParseScript(strings.NewReader(test.script))
+			var texts []string
+			for it.Next() {
+				texts = append(texts, it.Text())
+			}
+			checkError(t, it.Err(), test.err)
+			if !reflect.DeepEqual(texts, test.texts) {
+				t.Fatalf("expected the statements %q, got %q", test.texts, texts)
+			}
+		})
+	}
+}
+
+func TestParseScriptStatements(t *testing.T) {
+	it := newTestParser().ParseScript(strings.NewReader("SELECT id FROM users;\nINSERT INTO orders (id, user_id) VALUES (1, 2);"))
+	var types []QueryType
+	for it.Next() {
+		types = append(types, it.Statement().QueryType)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []QueryType{SelectQuery, InsertQuery}; !reflect.DeepEqual(types, want) {
+		t.Fatalf("expected the query types %v, got %v", want, types)
+	}
+	if it.Next() {
+		t.Fatal("Next returned true after the end of the script")
+	}
+}
+
+func TestParseScriptDialect(t *testing.T) {
+	// MySQL escapes quotes with backslashes and quotes identifiers with
+	// backquotes, the semicolons they hide do not end the statement
+	parser := newTestParser()
+	parser.Dialect = MySQLDialect
+	it := parser.ParseScript(strings.NewReader("SELECT id FROM users WHERE name = 'a\\';b';SELECT `ID;` FROM users"))
+	var texts []string
+	for it.Next() {
+		texts = append(texts, it.Text())
+	}
+	checkError(t, it.Err(), `statement 2 (line 1): column "ID;" does not exist in table USERS`)
+	if want := []string{"SELECT id FROM users WHERE name = 'a\\';b'"}; !reflect.DeepEqual(texts, want) {
+		t.Fatalf("expected the statements %q, got %q", want, texts)
+	}
+}
@@ -0,0 +1,115 @@
+package sqlParser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ShowObject is the catalog information an introspection statement asks for.
+type ShowObject string
+
+const (
+	ShowTables  ShowObject = "TABLES"
+	ShowColumns ShowObject = "COLUMNS"
+	ShowIndexes ShowObject = "INDEXES"
+)
+
+// ShowStmt represents an introspection statement: SHOW TABLES, SHOW COLUMNS
+// FROM table, DESCRIBE table or SHOW INDEXES FROM table. It is answered from
+// the schema by Schema.ShowRows.
+type ShowStmt struct {
+	Trivia
+	Object   ShowObject
+	Table    TableName // the table of SHOW COLUMNS, DESCRIBE and SHOW INDEXES
+	Describe bool      // SHOW COLUMNS was written DESCRIBE
+}
+
+func (stmt *ShowStmt) QueryType() QueryType { return ShowQuery }
+
+// showColumns names the columns of the answer to each introspection
+// statement.
+var showColumns = map[ShowObject][]string{
+	ShowTables:  {"NAME", "TYPE"},
+	ShowColumns: {"NAME", "TYPE", "KEY"},
+	ShowIndexes: {"NAME", "COLUMNS", "TYPE"},
+}
+
+// showResult returns the columns of the answer to an introspection
+// statement, all of them texts.
+func showResult(stmt *ShowStmt) []ResultColumn {
+	var result []ResultColumn
+	for _, name := range showColumns[stmt.Object] {
+		result = append(result, ResultColumn{Name: name, Type: TextType})
+	}
+	return result
+}
+
+// ShowRows answers an introspection statement from the schema, without a
+// storage engine, and returns the names of the columns of the answer and its
+// rows:
+//
+//	SHOW TABLES               NAME, TYPE (TABLE or VIEW)
+//	SHOW COLUMNS, DESCRIBE    NAME, TYPE, KEY (PRIMARY, UNIQUE or empty)
+//	SHOW INDEXES              NAME, COLUMNS, TYPE (PRIMARY, UNIQUE or INDEX)
+func (schema *Schema) ShowRows(stmt *ShowStmt) (columns []string, rows [][]string, err error) {
+	columns = showColumns[stmt.Object]
+	if stmt.Object == ShowTables {
+		// the tables of the current schema
+		current := schema.child(schema.CurrentSchema())
+		if current == nil {
+			return nil, nil, fmt.Errorf("schema %s does not exist", schema.CurrentSchema())
+		}
+		for _, name := range current.GetSchemaTables() {
+			rows = append(rows, []string{name, "TABLE"})
+		}
+		for _, name := range current.GetSchemaViews() {
+			rows = append(rows, []string{name, "VIEW"})
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+		return columns, rows, nil
+	}
+
+	table, err := schema.LookupTable(stmt.Table)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch stmt.Object {
+	case ShowColumns:
+		for _, name := range schema.GetTableColumns(table) {
+			rows = append(rows, []string{name, schema.GetColumnDataType(table, name), schema.columnKey(table, name)})
+		}
+	case ShowIndexes:
+		for _, index := range schema.GetTableIndexes(table) {
+			kind := "INDEX"
+			switch {
+			case index.Primary:
+				kind = "PRIMARY"
+			case index.Unique:
+				kind = "UNIQUE"
+			}
+			rows = append(rows, []string{index.Name, strings.Join(index.Columns, ", "), kind})
+		}
+	default:
+		return nil, nil, fmt.Errorf("SHOW %s is not supported", stmt.Object)
+	}
+	return columns, rows, nil
+}
+
+// columnKey tells whether the column is the primary key of the table, or
+// alone in a unique index.
+func (schema *Schema) columnKey(table, column string) string {
+	key := ""
+	for _, index := range schema.GetTableIndexes(table) {
+		if len(index.Columns) != 1 || index.Columns[0] != column {
+			continue
+		}
+		if index.Primary {
+			return "PRIMARY"
+		}
+		if index.Unique {
+			key = "UNIQUE"
+		}
+	}
+	return key
+}
@@ -0,0 +1,75 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShow(t *testing.T) {
+	runParseTests(t, newViewParser, []parseTest{
+		{name: "tables", sql: "SHOW TABLES"},
+		{name: "columns from", sql: "SHOW COLUMNS FROM users"},
+		{name: "columns in", sql: "SHOW COLUMNS IN orders"},
+		{name: "columns of a view", sql: "SHOW COLUMNS FROM adults"},
+		{name: "describe", sql: "DESCRIBE users"},
+		{name: "desc", sql: "DESC orders"},
+		{name: "index", sql: "SHOW INDEX FROM users"},
+		{name: "indexes", sql: "SHOW INDEXES IN users"},
+		{name: "keys", sql: "SHOW KEYS FROM orders"},
+		{name: "unknown object", sql: "SHOW USERS", err: "TABLES, COLUMNS or INDEXES"},
+		{name: "missing from", sql: "SHOW COLUMNS users", err: "FROM"},
+		{name: "missing table", sql: "SHOW INDEXES FROM", err: "missing table name"},
+		{name: "describe without table", sql: "DESCRIBE", err: "missing table name"},
+		{name: "unknown table", sql: "SHOW COLUMNS FROM nope", err: "table NOPE does not exist"},
+		{name: "describe unknown table", sql: "DESCRIBE nope", err: "table NOPE does not exist"},
+		{name: "trailing tokens", sql: "SHOW TABLES FROM users", err: "syntax error"},
+	})
+}
+
+func TestShowRows(t *testing.T) {
+	tests := []struct {
+		sql     string
+		columns []string
+		rows    [][]string
+	}{
+		{
+			sql:     "SHOW TABLES",
+			columns: []string{"NAME", "TYPE"},
+			rows:    [][]string{{"ADULTS", "VIEW"}, {"BUYERS", "VIEW"}, {"NAMED", "VIEW"}, {"ORDERS", "TABLE"}, {"RANKED", "VIEW"}, {"RECENT", "VIEW"}, {"TOTALS", "VIEW"}, {"USERS", "TABLE"}},
+		},
+		{
+			sql:     "DESCRIBE users",
+			columns: []string{"NAME", "TYPE", "KEY"},
+			rows:    [][]string{{"ID", "bigint", "PRIMARY"}, {"NAME", "varchar(50)", ""}, {"AGE", "int", ""}, {"EMAIL", "text", "UNIQUE"}},
+		},
+		{
+			sql:     "SHOW INDEXES FROM users",
+			columns: []string{"NAME", "COLUMNS", "TYPE"},
+			rows:    [][]string{{"USERS_PK", "ID", "PRIMARY"}, {"USERS_EMAIL", "EMAIL", "UNIQUE"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.sql, func(t *testing.T) {
+			parser := newViewParser()
+			parsedStmt := mustParse(t, parser, test.sql)
+			var result []string
+			for _, column := range parsedStmt.Result {
+				result = append(result, column.Name)
+			}
+			if !reflect.DeepEqual(result, test.columns) {
+				t.Fatalf("expected the result columns %v, got %v", test.columns, result)
+			}
+
+			columns, rows, err := parser.Schema.ShowRows(parsedStmt.Stmt.(*ShowStmt))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(columns, test.columns) {
+				t.Fatalf("expected the columns %v, got %v", test.columns, columns)
+			}
+			if !reflect.DeepEqual(rows, test.rows) {
+				t.Fatalf("expected the rows %q, got %q", test.rows, rows)
+			}
+		})
+	}
+}
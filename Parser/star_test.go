@@ -0,0 +1,55 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStarExpansion(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		columns []string
+		err     string
+	}{
+		{name: "star", sql: "SELECT * FROM users", columns: []string{"ID", "NAME", "AGE", "EMAIL"}},
+		{name: "table star", sql: "SELECT users.* FROM users", columns: []string{"ID", "NAME", "AGE", "EMAIL"}},
+		{name: "star and columns", sql: "SELECT id AS key, * FROM users", columns: []string{"KEY", "ID", "NAME", "AGE", "EMAIL"}},
+		{name: "star of a joined table", sql: "DELETE FROM orders USING users WHERE orders.user_id = users.id RETURNING users.*, total", columns: []string{"ID", "NAME", "AGE", "EMAIL", "TOTAL"}},
+		{name: "star of every table", sql: "DELETE FROM orders USING users WHERE orders.user_id = users.id RETURNING *", columns: []string{"ID", "USER_ID", "TOTAL", "CREATED_AT", "ID", "NAME", "AGE", "EMAIL"}},
+		{name: "star of another table", sql: "SELECT orders.* FROM users", err: "table ORDERS of ORDERS.* is not part of the statement"},
+		{name: "star of an unknown table", sql: "SELECT * FROM nope", err: "table NOPE does not exist in the schema"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsedStmt, err := newTestParser().ParseSQL(test.sql)
+			checkError(t, err, test.err)
+			if test.err != "" {
+				return
+			}
+			var columns []string
+			for _, column := range parsedStmt.Result {
+				columns = append(columns, column.Name)
+			}
+			if !reflect.DeepEqual(columns, test.columns) {
+				t.Fatalf("expected the columns %v, got %v", test.columns, columns)
+			}
+		})
+	}
+}
+
+func TestStarResult(t *testing.T) {
+	parsedStmt := mustParse(t, newTestParser(), "SELECT * FROM orders")
+	want := []ResultColumn{
+		{Name: "ID", Type: IntegerType, Table: "ORDERS", Column: "ID"},
+		{Name: "USER_ID", Type: IntegerType, Table: "ORDERS", Column: "USER_ID"},
+		{Name: "TOTAL", Type: DecimalType, Table: "ORDERS", Column: "TOTAL"},
+		{Name: "CREATED_AT", Type: TimestampType, Table: "ORDERS", Column: "CREATED_AT"},
+	}
+	if !reflect.DeepEqual(parsedStmt.Result, want) {
+		t.Fatalf("expected the result %+v, got %+v", want, parsedStmt.Result)
+	}
+	if want := []string{"ID", "USER_ID", "TOTAL", "CREATED_AT"}; !reflect.DeepEqual(parsedStmt.Columns, want) {
+		t.Fatalf("expected the columns %v, got %v", want, parsedStmt.Columns)
+	}
+}
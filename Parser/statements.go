@@ -1,53 +1,209 @@
 package sqlParser
 
+import "strings"
 
-/* here we define the struct and interface definitions of the statments 
- that we gonna use .
+/* here we define the struct and interface definitions of the statments
+that we gonna use, together they make up the AST built by the parser.
 */
 
+// Statement is implemented by every statement of the AST.
+type Statement interface {
+	QueryType() QueryType
+	attach(comments []Comment)
+}
+
+// Trivia holds the comments attached to an AST node, it is only filled when
+// SQLParser.KeepComments is set.
+type Trivia struct {
+	Comments []Comment
+}
+
+func (trivia *Trivia) attach(comments []Comment) {
+	trivia.Comments = append(trivia.Comments, comments...)
+}
+
 // SelectStmt represents a SELECT statement.
 type SelectStmt struct {
-    Tables     []string
-    Columns    []string
-    Conditions []Condition
-    Limit      int
+	Trivia
+	Distinct   bool         // SELECT DISTINCT, or DISTINCT ON when DistinctOn is set
+	All        bool         // SELECT ALL, the default written out
+	DistinctOn []Expr       // the expressions of DISTINCT ON (...)
+	Columns    []SelectItem // the select list
+	Tables     []TableName
+	Where      Expr // nil without WHERE
+	GroupBy    []Expr
+	Windows    []NamedWindow // the windows of the WINDOW clause
+	OrderBy    []OrderItem
+	Limit      Expr // nil without LIMIT
+	Offset     Expr // nil without OFFSET
 }
 
 // UpdateStmt represents an UPDATE statement.
 type UpdateStmt struct {
-    Table      string
-    SetValues  map[string]interface{}
-    Conditions []Condition
+	Trivia
+	Table     TableName
+	Joined    []TableName  // more updated tables: UPDATE t1, t2 SET ... (MySQL)
+	Set       []Assignment // the SET items in the order they were written
+	From      []TableName  // tables joined by UPDATE ... FROM
+	Where     Expr
+	OrderBy   []OrderItem
+	Limit     Expr // nil without LIMIT
+	Returning []SelectItem
+}
+
+// tables returns the updated tables followed by the FROM ones.
+func (stmt *UpdateStmt) tables() []TableName {
+	tables := append([]TableName{stmt.Table}, stmt.Joined...)
+	return append(tables, stmt.From...)
 }
 
+// Assignment is an item of a SET clause: column = value, or
+// (col1, col2) = (value1, value2) assigning a row value.
+type Assignment struct {
+	Trivia
+	Columns []*ColumnRef
+	Values  []Expr
+}
+
+func (assignment Assignment) String() string {
+	if len(assignment.Columns) == 1 && len(assignment.Values) == 1 {
+		return assignment.Columns[0].String() + " = " + assignment.Values[0].String()
+	}
+	columns := make([]string, 0, len(assignment.Columns))
+	for _, column := range assignment.Columns {
+		columns = append(columns, column.String())
+	}
+	return "(" + strings.Join(columns, ", ") + ") = (" + strings.Join(exprStrings(assignment.Values), ", ") + ")"
+}
+
+// OrderItem is an expression of an ORDER BY clause.
+type OrderItem struct {
+	Expr Expr
+	Desc bool
+}
+
+func (item OrderItem) String() string {
+	if item.Desc {
+		return item.Expr.String() + " DESC"
+	}
+	return item.Expr.String()
+}
 
-// InsertStmt represents an INSERT statement.
+// InsertStmt represents an INSERT statement. The rows come from exactly one
+// of Rows, Select and DefaultValues.
 type InsertStmt struct {
-    Table   string
-    Columns []string
-    Values  []interface{}
+	Trivia
+	Table         TableName
+	Columns       []Identifier // empty when the schema column order is used
+	Rows          [][]Expr     // VALUES (...), (...)
+	Select        *SelectStmt  // INSERT ... SELECT
+	DefaultValues bool         // INSERT ... DEFAULT VALUES
+	RowAlias      Identifier   // VALUES (...) AS alias, naming the proposed row in ON DUPLICATE KEY UPDATE
+	OnConflict    *OnConflict  // nil when the statement is not an upsert
+	Returning     []SelectItem
+}
+
+// OnConflict is the clause turning an INSERT into an upsert, either
+// ON CONFLICT [(columns) | ON CONSTRAINT name] DO NOTHING | DO UPDATE SET ... [WHERE ...]
+// or ON DUPLICATE KEY UPDATE ...
+// In the SET values and conditions EXCLUDED.column is the value the row
+// would have been inserted with. ON DUPLICATE KEY UPDATE refers to it by
+// VALUES(column), or by alias.column with the row alias of the INSERT.
+type OnConflict struct {
+	Trivia
+	DuplicateKey bool         // ON DUPLICATE KEY UPDATE
+	Target       []Identifier // the conflict target columns
+	Constraint   Identifier   // the conflict target index of ON CONSTRAINT
+	DoNothing    bool
+	Set          []Assignment
+	Where        Expr
 }
 
 // DeleteStmt represents a DELETE statement.
 type DeleteStmt struct {
-	Table      string
-	Conditions string
+	Trivia
+	Table     TableName
+	Using     []TableName // tables joined by DELETE ... USING
+	Where     Expr
+	OrderBy   []OrderItem
+	Limit     Expr // nil without LIMIT
+	Returning []SelectItem
+}
+
+// tables returns the table rows are deleted from followed by the USING ones.
+func (stmt *DeleteStmt) tables() []TableName {
+	return append([]TableName{stmt.Table}, stmt.Using...)
 }
 
+// Drop represents a DROP TABLE, DROP INDEX or DROP VIEW statement.
 type Drop struct {
-    Table string
+	Trivia
+	Table TableName
+	Index Identifier
+	View  TableName
 }
 
+// Create represents a CREATE TABLE or CREATE INDEX statement.
 type Create struct {
-    Table string
-    Columns []string
+	Trivia
+	Table   TableName
+	Index   Identifier
+	Columns []Identifier
+}
+
+// TruncateStmt represents a TRUNCATE statement, which deletes every row of
+// the tables.
+type TruncateStmt struct {
+	Trivia
+	Tables          []TableName
+	RestartIdentity bool // RESTART IDENTITY resets the sequences of the tables, CONTINUE IDENTITY keeps them
+	Cascade         bool // CASCADE also truncates the tables referencing them, RESTRICT refuses to
+}
+
+// ExplainStmt represents an EXPLAIN statement, which asks for the plan of
+// the statement it wraps.
+type ExplainStmt struct {
+	Trivia
+	Analyze bool // the statement is run and the plan shows its actual costs
+	Verbose bool
+	Format  string    // TEXT, JSON, XML or YAML, empty without FORMAT
+	Stmt    Statement // the explained statement
+}
+
+// CreateView represents a CREATE [OR REPLACE] VIEW statement.
+type CreateView struct {
+	Trivia
+	OrReplace bool
+	Name      TableName
+	Columns   []Identifier // the names given to the columns of the query, empty to keep theirs
+	Select    *SelectStmt
 }
-type Condition struct {
-    Column string
-    Operator string
-    Value string
+
+// SelectItem is an expression of a select list or RETURNING clause, with
+// the alias naming it in the result. The expression is a *Star for *.
+type SelectItem struct {
+	Expr  Expr
+	Alias Identifier // empty without AS alias
 }
 
+func (item SelectItem) String() string {
+	if item.Alias.IsEmpty() {
+		return item.Expr.String()
+	}
+	return item.Expr.String() + " AS " + item.Alias.String()
+}
+
+// operator is an AND or OR joining the conditions of ParsedStmt.Conditions.
 type operator struct {
-    operator string
+	operator string
 }
+
+func (stmt *SelectStmt) QueryType() QueryType   { return SelectQuery }
+func (stmt *UpdateStmt) QueryType() QueryType   { return UpdateQuery }
+func (stmt *InsertStmt) QueryType() QueryType   { return InsertQuery }
+func (stmt *DeleteStmt) QueryType() QueryType   { return DeleteQuery }
+func (stmt *Drop) QueryType() QueryType         { return DropQuery }
+func (stmt *Create) QueryType() QueryType       { return CreateQuery }
+func (stmt *CreateView) QueryType() QueryType   { return CreateQuery }
+func (stmt *TruncateStmt) QueryType() QueryType { return TruncateQuery }
+func (stmt *ExplainStmt) QueryType() QueryType  { return ExplainQuery }
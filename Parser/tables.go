@@ -0,0 +1,51 @@
+package sqlParser
+
+import "fmt"
+
+// validateCreateTable checks that the table of a CREATE TABLE statement
+// does not exist yet in the schema it is created in, and that its columns
+// are named once, and returns its name as LookupTable would.
+func (parser *SQLParser) validateCreateTable(stmt *Create) (string, error) {
+	schemaName, namespace, err := parser.Schema.creationSchema(stmt.Table)
+	if err != nil {
+		return "", err
+	}
+	if table, ok := stmt.Table.Name.resolve(namespace.GetSchemaTables()); ok {
+		return "", fmt.Errorf("table %s already exists", parser.Schema.qualify(schemaName, table))
+	}
+	if view, ok := stmt.Table.Name.resolve(namespace.GetSchemaViews()); ok {
+		return "", fmt.Errorf("view %s already exists", parser.Schema.qualify(schemaName, view))
+	}
+	if valid, column := containsNoDuplicates(identifierNames(stmt.Columns)); !valid {
+		return "", fmt.Errorf("column %s is specified more than once", column)
+	}
+	return parser.Schema.qualify(schemaName, stmt.Table.Name.Name), nil
+}
+
+// validateCreateIndex checks that the index of a CREATE INDEX statement is
+// new in the schema of its table, and that it covers existing columns of a
+// table, and returns the table and the columns as stored in the schema.
+func (parser *SQLParser) validateCreateIndex(stmt *Create) (string, []string, error) {
+	tables, err := parser.validateTableExistence([]TableName{stmt.Table})
+	if err != nil {
+		return "", nil, err
+	}
+	if parser.Schema.IsView(tables[0]) {
+		return "", nil, fmt.Errorf("%s is a view, it can not be indexed", tables[0])
+	}
+	columns, err := parser.validateColumnExistence(tables, columnRefs(stmt.Columns))
+	if err != nil {
+		return "", nil, err
+	}
+	if valid, column := containsNoDuplicates(columns); !valid {
+		return "", nil, fmt.Errorf("column %s is specified more than once", column)
+	}
+
+	_, namespace, _ := parser.Schema.namespace(tables[0])
+	for index := range namespace.indexNames() {
+		if stmt.Index.Matches(index) {
+			return "", nil, fmt.Errorf("index %s already exists", index)
+		}
+	}
+	return tables[0], columns, nil
+}
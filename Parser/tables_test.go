@@ -0,0 +1,49 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCreateTableAndIndex(t *testing.T) {
+	runParseTests(t, newViewParser, []parseTest{
+		{name: "create table", sql: "CREATE TABLE notes (id, body)"},
+		{name: "create index", sql: "CREATE INDEX users_name ON users (name, age)"},
+		{name: "drop a table read by a view", sql: "DROP TABLE orders", err: "can not drop table ORDERS because view"},
+		{name: "drop index", sql: "DROP INDEX users_email"},
+		{name: "existing table", sql: "CREATE TABLE users (id)", err: "table USERS already exists"},
+		{name: "existing view", sql: "CREATE TABLE adults (id)", err: "view ADULTS already exists"},
+		{name: "duplicate table column", sql: "CREATE TABLE notes (id, ID)", err: "column ID is specified more than once"},
+		{name: "index of an unknown table", sql: "CREATE INDEX i ON nope (id)", err: "table NOPE does not exist"},
+		{name: "index of a view", sql: "CREATE INDEX i ON adults (id)", err: "ADULTS is a view, it can not be indexed"},
+		{name: "index of an unknown column", sql: "CREATE INDEX i ON users (nope)", err: "column NOPE does not exist"},
+		{name: "duplicate index column", sql: "CREATE INDEX i ON users (name, name)", err: "column NAME is specified more than once"},
+		{name: "existing index", sql: "CREATE INDEX users_email ON users (name)", err: "index USERS_EMAIL already exists"},
+		{name: "index existing on another table", sql: "CREATE INDEX orders_pk ON users (name)", err: "index ORDERS_PK already exists"},
+		{name: "drop unknown index", sql: "DROP INDEX nope", err: "index NOPE does not exist in the schema"},
+	})
+}
+
+func TestCreateTableAndIndexCatalog(t *testing.T) {
+	parser := newTestParser()
+	mustParse(t, parser, "CREATE TABLE notes (id, body)")
+	mustParse(t, parser, "CREATE INDEX notes_body ON notes (body)")
+	if want := []string{"ID", "BODY"}; !reflect.DeepEqual(parser.Schema.GetTableColumns("NOTES"), want) {
+		t.Fatalf("expected the columns %v, got %v", want, parser.Schema.GetTableColumns("NOTES"))
+	}
+	if want := []Index{{Name: "NOTES_BODY", Columns: []string{"BODY"}}}; !reflect.DeepEqual(parser.Schema.GetTableIndexes("NOTES"), want) {
+		t.Fatalf("expected the indexes %v, got %v", want, parser.Schema.GetTableIndexes("NOTES"))
+	}
+
+	parsedStmt := mustParse(t, parser, "DROP INDEX notes_body")
+	if want := []string{"NOTES"}; !reflect.DeepEqual(parsedStmt.Tables, want) {
+		t.Fatalf("expected DROP INDEX to report the table %v, got %v", want, parsedStmt.Tables)
+	}
+	if indexes := parser.Schema.GetTableIndexes("NOTES"); len(indexes) != 0 {
+		t.Fatalf("expected no index left, got %v", indexes)
+	}
+
+	mustParse(t, parser, "DROP TABLE notes")
+	_, err := parser.ParseSQL("SELECT id FROM notes")
+	checkError(t, err, "table NOTES does not exist")
+}
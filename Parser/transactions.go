@@ -0,0 +1,97 @@
+package sqlParser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TransactionAction is the action of a transaction control statement.
+type TransactionAction string
+
+const (
+	BeginTransaction    TransactionAction = "BEGIN" // BEGIN or START TRANSACTION
+	CommitTransaction   TransactionAction = "COMMIT"
+	RollbackTransaction TransactionAction = "ROLLBACK"
+	SavepointAction     TransactionAction = "SAVEPOINT"
+	ReleaseSavepoint    TransactionAction = "RELEASE"
+	RollbackToSavepoint TransactionAction = "ROLLBACK TO"
+)
+
+// TransactionStmt represents a transaction control statement: BEGIN, START
+// TRANSACTION, COMMIT, ROLLBACK, SAVEPOINT, RELEASE or ROLLBACK TO.
+type TransactionStmt struct {
+	Trivia
+	Action    TransactionAction
+	Savepoint Identifier // the savepoint of SAVEPOINT, RELEASE and ROLLBACK TO
+	Isolation string     // the isolation level of BEGIN: SERIALIZABLE, REPEATABLE READ, READ COMMITTED or READ UNCOMMITTED
+	ReadOnly  bool       // BEGIN ... READ ONLY
+	ReadWrite bool       // BEGIN ... READ WRITE
+}
+
+func (stmt *TransactionStmt) QueryType() QueryType { return TransactionQuery }
+
+// TransactionChecker follows the transaction control statements of a script
+// and reports the ones that do not fit its structure, such as a COMMIT with
+// no open transaction or a ROLLBACK TO an unknown savepoint.
+type TransactionChecker struct {
+	open       bool
+	savepoints []string // savepoints of the open transaction, the latest last
+}
+
+// Check checks the statement against the transactions opened by the
+// statements checked before it.
+func (checker *TransactionChecker) Check(stmt Statement) error {
+	tx, ok := stmt.(*TransactionStmt)
+	if !ok {
+		return nil
+	}
+	if tx.Action == BeginTransaction {
+		if checker.open {
+			return errors.New("BEGIN inside a transaction that is already open")
+		}
+		checker.open = true
+		return nil
+	}
+	if !checker.open {
+		return fmt.Errorf("%s without an open transaction", tx.Action)
+	}
+
+	switch tx.Action {
+	case CommitTransaction, RollbackTransaction:
+		checker.open = false
+		checker.savepoints = nil
+	case SavepointAction:
+		checker.savepoints = append(checker.savepoints, tx.Savepoint.Name)
+	case ReleaseSavepoint, RollbackToSavepoint:
+		i := checker.lookupSavepoint(tx.Savepoint)
+		if i < 0 {
+			return fmt.Errorf("savepoint %s does not exist", tx.Savepoint)
+		}
+		// RELEASE also releases the savepoint itself, ROLLBACK TO keeps it
+		if tx.Action == ReleaseSavepoint {
+			checker.savepoints = checker.savepoints[:i]
+		} else {
+			checker.savepoints = checker.savepoints[:i+1]
+		}
+	}
+	return nil
+}
+
+// lookupSavepoint returns the index of the latest savepoint of the name, -1
+// when there is none.
+func (checker *TransactionChecker) lookupSavepoint(name Identifier) int {
+	for i := len(checker.savepoints) - 1; i >= 0; i-- {
+		if name.Matches(checker.savepoints[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// End reports a transaction left open at the end of the script.
+func (checker *TransactionChecker) End() error {
+	if checker.open {
+		return errors.New("transaction is still open at the end of the script")
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package sqlParser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTransactions(t *testing.T) {
+	runParseTests(t, newTestParser, []parseTest{
+		{name: "begin", sql: "BEGIN"},
+		{name: "begin transaction", sql: "BEGIN TRANSACTION"},
+		{name: "begin work", sql: "BEGIN WORK"},
+		{name: "start transaction", sql: "START TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY"},
+		{name: "isolation levels", sql: "BEGIN ISOLATION LEVEL READ UNCOMMITTED READ WRITE"},
+		{name: "commit", sql: "COMMIT WORK"},
+		{name: "rollback", sql: "ROLLBACK TRANSACTION"},
+		{name: "savepoint", sql: "SAVEPOINT before_update"},
+		{name: "release", sql: "RELEASE SAVEPOINT before_update"},
+		{name: "rollback to", sql: "ROLLBACK TO SAVEPOINT before_update"},
+		{name: "start without transaction", sql: "START", err: "TRANSACTION"},
+		{name: "missing savepoint name", sql: "SAVEPOINT", err: "a savepoint name"},
+		{name: "rollback to without name", sql: "ROLLBACK TO", err: "a savepoint name"},
+		{name: "unknown isolation level", sql: "BEGIN ISOLATION LEVEL CHAOS", err: "an isolation level"},
+		{name: "read what", sql: "BEGIN READ SOMETHING", err: "ONLY or WRITE"},
+		{name: "isolation level twice", sql: "BEGIN ISOLATION LEVEL SERIALIZABLE, ISOLATION LEVEL READ COMMITTED", err: "syntax error: the isolation level is specified more than once"},
+		{name: "read only and read write", sql: "BEGIN READ ONLY, READ WRITE", err: "syntax error: a transaction can not be both READ ONLY and READ WRITE"},
+		{name: "dangling comma", sql: "BEGIN READ ONLY,", err: "a transaction mode"},
+	})
+}
+
+func TestTransactionStatement(t *testing.T) {
+	tests := []struct {
+		sql       string
+		action    TransactionAction
+		savepoint string
+		isolation string
+		readOnly  bool
+	}{
+		{sql: "START TRANSACTION ISOLATION LEVEL SERIALIZABLE READ ONLY", action: BeginTransaction, isolation: "SERIALIZABLE", readOnly: true},
+		{sql: "BEGIN", action: BeginTransaction},
+		{sql: "COMMIT", action: CommitTransaction},
+		{sql: "ROLLBACK WORK", action: RollbackTransaction},
+		{sql: "SAVEPOINT sp", action: SavepointAction, savepoint: "SP"},
+		{sql: "RELEASE sp", action: ReleaseSavepoint, savepoint: "SP"},
+		{sql: "ROLLBACK TO sp", action: RollbackToSavepoint, savepoint: "SP"},
+	}
+	for _, test := range tests {
+		t.Run(test.sql, func(t *testing.T) {
+			parsedStmt := mustParse(t, newTestParser(), test.sql)
+			if parsedStmt.QueryType != TransactionQuery {
+				t.Fatalf("expected a %s query, got %s", TransactionQuery, parsedStmt.QueryType)
+			}
+			stmt := parsedStmt.Stmt.(*TransactionStmt)
+			if stmt.Action != test.action || stmt.Savepoint.Name != test.savepoint || stmt.Isolation != test.isolation || stmt.ReadOnly != test.readOnly {
+				t.Fatalf("expected %s %q %q read only %v, got %s %q %q read only %v",
+					test.action, test.savepoint, test.isolation, test.readOnly,
+					stmt.Action, stmt.Savepoint.Name, stmt.Isolation, stmt.ReadOnly)
+			}
+		})
+	}
+}
+
+func TestCheckTransactions(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		err    string
+	}{
+		{name: "commit", script: "BEGIN; UPDATE users SET age = 1; COMMIT;"},
+		{name: "statements outside transactions", script: "SELECT id FROM users; BEGIN; ROLLBACK; DELETE FROM orders;"},
+		{name: "two transactions", script: "BEGIN; COMMIT; START TRANSACTION; ROLLBACK;"},
+		{name: "savepoints", script: "BEGIN; SAVEPOINT a; SAVEPOINT b; ROLLBACK TO a; SAVEPOINT c; RELEASE a; COMMIT;"},
+		{name: "rollback to keeps the savepoint", script: "BEGIN; SAVEPOINT a; ROLLBACK TO a; ROLLBACK TO a; COMMIT;"},
+		{name: "nested begin", script: "BEGIN; BEGIN; COMMIT;", err: "statement 2 (line 1): BEGIN inside a transaction that is already open"},
+		{name: "commit without begin", script: "SELECT id FROM users;\nCOMMIT;", err: "statement 2 (line 2): COMMIT without an open transaction"},
+		{name: "savepoint without begin", script: "SAVEPOINT a;", err: "SAVEPOINT without an open transaction"},
+		{name: "savepoint of a closed transaction", script: "BEGIN; SAVEPOINT a; COMMIT; BEGIN; ROLLBACK TO a;", err: "statement 5 (line 1): savepoint A does not exist"},
+		{name: "released savepoint", script: "BEGIN; SAVEPOINT a; SAVEPOINT b; RELEASE a; RELEASE b;", err: "savepoint B does not exist"},
+		{name: "open at the end", script: "BEGIN; UPDATE users SET age = 1;", err: "transaction is still open at the end of the script"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parser := newTestParser()
+			parser.CheckTransactions = true
+			it := parser.ParseScript(strings.NewReader(test.script))
+			for it.Next() {
+			}
+			checkError(t, it.Err(), test.err)
+		})
+	}
+}
+
+func TestTransactionsNotChecked(t *testing.T) {
+	it := newTestParser().ParseScript(strings.NewReader("COMMIT; BEGIN; BEGIN; ROLLBACK TO a; BEGIN;"))
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 5 {
+		t.Fatalf("expected 5 statements, got %d", count)
+	}
+}
@@ -0,0 +1,48 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+	runParseTests(t, newViewParser, []parseTest{
+		{name: "table", sql: "TRUNCATE orders"},
+		{name: "table keyword", sql: "TRUNCATE TABLE orders"},
+		{name: "several tables", sql: "TRUNCATE TABLE orders, users"},
+		{name: "identity and cascade", sql: "TRUNCATE users RESTART IDENTITY CASCADE"},
+		{name: "continue identity and restrict", sql: "TRUNCATE users CONTINUE IDENTITY RESTRICT"},
+		{name: "missing table name", sql: "TRUNCATE TABLE", err: "missing table name in TRUNCATE statement"},
+		{name: "restart without identity", sql: "TRUNCATE users RESTART", err: "IDENTITY"},
+		{name: "trailing tokens", sql: "TRUNCATE users WHERE id = 1", err: "syntax error"},
+		{name: "unknown table", sql: "TRUNCATE nope", err: "table NOPE does not exist"},
+		{name: "view", sql: "TRUNCATE adults", err: "ADULTS is a view, it can not be truncated"},
+	})
+}
+
+func TestTruncateStatement(t *testing.T) {
+	parsedStmt := mustParse(t, newTestParser(), "TRUNCATE TABLE orders, users RESTART IDENTITY CASCADE")
+	if parsedStmt.QueryType != TruncateQuery {
+		t.Fatalf("expected a %s query, got %s", TruncateQuery, parsedStmt.QueryType)
+	}
+	if want := []string{"ORDERS", "USERS"}; !reflect.DeepEqual(parsedStmt.Tables, want) {
+		t.Fatalf("expected the tables %v, got %v", want, parsedStmt.Tables)
+	}
+	stmt := parsedStmt.Stmt.(*TruncateStmt)
+	if !stmt.RestartIdentity || !stmt.Cascade {
+		t.Fatalf("expected RESTART IDENTITY and CASCADE, got %+v", stmt)
+	}
+	if !IsDestructiveWrite(stmt) {
+		t.Fatal("expected a TRUNCATE to be destructive")
+	}
+}
+
+func TestTruncateSafeMode(t *testing.T) {
+	parser := newTestParser()
+	parser.SafeMode = true
+	_, err := parser.ParseSQL("TRUNCATE orders, users")
+	checkError(t, err, "safe mode: TRUNCATE deletes every row of table ORDERS")
+
+	parser.AllowFullTableWrites = true
+	mustParse(t, parser, "TRUNCATE orders, users")
+}
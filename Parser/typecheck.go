@@ -0,0 +1,269 @@
+package sqlParser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// assignable reports whether a value of type from can be stored in a column
+// of type to. Numbers convert to each other and to text.
+func assignable(from, to DataType) bool {
+	if _, ok := commonType(from, to); ok {
+		return true
+	}
+	return from.IsNumeric() && to == TextType
+}
+
+// checkAssignment verifies that the value can be stored in a column of the
+// given type. Only constants are checked, string constants are accepted for
+// any type they hold a valid value of, as in '42' for an integer column.
+func checkAssignment(value Expr, column string, columnType DataType) error {
+	lit, ok := value.(*Literal)
+	if !ok || columnType == UnknownType {
+		return nil
+	}
+	if assignable(lit.Type(), columnType) {
+		return nil
+	}
+	if lit.Kind == StringLiteral && convertible(lit.Value.(string), columnType) {
+		return nil
+	}
+	return fmt.Errorf("value %s does not match the type %s of column %s", lit.Raw, columnType, column)
+}
+
+// convertible reports whether the text is a valid value of the type.
+func convertible(text string, typ DataType) bool {
+	text = strings.TrimSpace(text)
+	switch typ {
+	case IntegerType:
+		_, err := strconv.ParseInt(text, 10, 64)
+		return err == nil
+	case DecimalType, FloatType:
+		_, err := strconv.ParseFloat(text, 64)
+		return err == nil
+	case BooleanType:
+		switch strings.ToLower(text) {
+		case "true", "false", "t", "f", "yes", "no", "on", "off", "1", "0":
+			return true
+		}
+		return false
+	case DateType, TimeType, TimestampType:
+		for _, layout := range typedLiteralLayouts[string(typ)] {
+			if _, err := time.Parse(layout, text); err == nil {
+				return true
+			}
+		}
+		return false
+	case IntervalType:
+		return text != ""
+	}
+	return true
+}
+
+// exprScope is what the expressions of a statement are analyzed against.
+type exprScope struct {
+	tables         []string      // resolved tables whose columns can be referenced
+	excluded       string        // the table of the row proposed by an upsert, in its SET values and conditions
+	excludedName   Identifier    // the name the proposed row is read by: EXCLUDED, or the row alias of ON DUPLICATE KEY UPDATE
+	insertedValues bool          // VALUES(column) reads the proposed row, in ON DUPLICATE KEY UPDATE
+	aggregates     bool          // aggregate functions can be called
+	windows        bool          // window functions can be called, in the select list and ORDER BY of a SELECT
+	namedWindows   []NamedWindow // the windows of the WINDOW clause OVER name refers to
+	params         *paramSet
+	reads          columnReads // gathers the columns read, nil when they are not checked
+}
+
+// resolveColumn resolves a column referenced by an expression of the scope.
+func (scope exprScope) resolveColumn(parser *SQLParser, column *ColumnRef) (table, name string, err error) {
+	if scope.excluded != "" && !scope.excludedName.IsEmpty() && column.Schema.IsEmpty() && column.Table.Matches(scope.excludedName.Name) {
+		return parser.resolveColumn([]string{scope.excluded}, &ColumnRef{Column: column.Column})
+	}
+	if len(scope.tables) == 0 {
+		return "", "", fmt.Errorf("column %s can not be used here", column)
+	}
+	table, name, err = parser.resolveColumn(scope.tables, column)
+	if err != nil {
+		return "", "", err
+	}
+	scope.reads.add(table, name)
+	return table, name, nil
+}
+
+// exprType resolves the columns of the expression and returns the type of
+// its value. A parameter takes the type of the operand it is combined with.
+func (parser *SQLParser) exprType(expr Expr, scope exprScope) (DataType, error) {
+	switch expr := expr.(type) {
+	case *Literal:
+		return expr.Type(), nil
+	case *Param:
+		return UnknownType, scope.params.add(expr, "", "", UnknownType)
+	case *Default:
+		return UnknownType, nil
+	case *ColumnRef:
+		table, name, err := scope.resolveColumn(parser, expr)
+		if err != nil {
+			return UnknownType, err
+		}
+		return parser.Schema.columnType(table, name), nil
+	case *InsertedValue:
+		if !scope.insertedValues {
+			return UnknownType, fmt.Errorf("%s can only be used in ON DUPLICATE KEY UPDATE", expr)
+		}
+		table, name, err := parser.resolveColumn([]string{scope.excluded}, &ColumnRef{Column: expr.Column})
+		if err != nil {
+			return UnknownType, err
+		}
+		return parser.Schema.columnType(table, name), nil
+	case *ParenExpr:
+		return parser.exprType(expr.Expr, scope)
+	case *UnaryExpr:
+		typ, err := parser.exprType(expr.Operand, scope)
+		if err != nil {
+			return UnknownType, err
+		}
+		if !typ.IsNumeric() && typ != UnknownType && typ != NullType && typ != IntervalType {
+			return UnknownType, fmt.Errorf("operator %s can not be applied to %s", expr.Operator, typ)
+		}
+		return typ, nil
+	case *BinaryExpr:
+		return parser.binaryExprType(expr, scope)
+	case *FuncCall:
+		return parser.funcCallType(expr, scope)
+	case *CastExpr:
+		return parser.castType(expr, scope)
+	case *CaseExpr:
+		return parser.caseType(expr, scope)
+	case *Comparison, *LogicalExpr, *NotExpr, *InExpr, *BetweenExpr, *LikeExpr, *IsNullExpr, *IsDistinctExpr:
+		return parser.predicateType(expr, scope)
+	}
+	return UnknownType, fmt.Errorf("%s can not be used as a value", expr)
+}
+
+// binaryExprType returns the type of an arithmetic or concatenation
+// operation.
+func (parser *SQLParser) binaryExprType(expr *BinaryExpr, scope exprScope) (DataType, error) {
+	left, err := parser.exprType(expr.Left, scope)
+	if err != nil {
+		return UnknownType, err
+	}
+	right, err := parser.exprType(expr.Right, scope)
+	if err != nil {
+		return UnknownType, err
+	}
+	if param, ok := expr.Left.(*Param); ok && expr.Operator != "||" {
+		if err := scope.params.add(param, "", "", right); err != nil {
+			return UnknownType, err
+		}
+		left = right
+	}
+	if param, ok := expr.Right.(*Param); ok && expr.Operator != "||" {
+		if err := scope.params.add(param, "", "", left); err != nil {
+			return UnknownType, err
+		}
+		right = left
+	}
+
+	if expr.Operator == "||" {
+		return TextType, nil
+	}
+	if common, ok := commonType(left, right); ok && (common.IsNumeric() || common == UnknownType || common == NullType) {
+		return common, nil
+	}
+	// date and time arithmetic: date + interval, timestamp - interval, interval + interval
+	switch {
+	case right == IntervalType && (expr.Operator == "+" || expr.Operator == "-") && isTemporal(left):
+		return left, nil
+	case left == IntervalType && expr.Operator == "+" && isTemporal(right):
+		return right, nil
+	case left == IntervalType && right.IsNumeric() && (expr.Operator == "*" || expr.Operator == "/"):
+		return IntervalType, nil
+	case left == DateType && right == IntegerType && (expr.Operator == "+" || expr.Operator == "-"):
+		return DateType, nil
+	case isTemporal(left) && left == right && expr.Operator == "-":
+		if left == DateType {
+			return IntegerType, nil
+		}
+		return IntervalType, nil
+	}
+	return UnknownType, fmt.Errorf("operator %s can not be applied to %s and %s", expr.Operator, left, right)
+}
+
+// isTemporal reports whether the type holds dates, times or intervals.
+func isTemporal(typ DataType) bool {
+	return typ == DateType || typ == TimeType || typ == TimestampType || typ == IntervalType
+}
+
+// checkValue verifies that the value can be stored in the column of the
+// table, the parameters are given the type of the column.
+func (parser *SQLParser) checkValue(value Expr, table, column string, scope exprScope) error {
+	columnType := parser.Schema.columnType(table, column)
+	switch value := value.(type) {
+	case *Param:
+		return scope.params.add(value, table, column, columnType)
+	case *Literal:
+		return checkAssignment(value, column, columnType)
+	}
+	typ, err := parser.exprType(value, scope)
+	if err != nil {
+		return err
+	}
+	if !assignable(typ, columnType) {
+		return fmt.Errorf("value %s of type %s does not match the type %s of column %s", value, typ, columnType, column)
+	}
+	return nil
+}
+
+// validateAssignments checks the SET items of an UPDATE or upsert, their
+// columns are resolved against the updated tables and their values against
+// the scope. The columns are returned as stored in the schema together with
+// their values, in order.
+func (parser *SQLParser) validateAssignments(set []Assignment, updated []string, scope exprScope) ([]string, []Expr, error) {
+	var columns []string
+	var values []Expr
+	seen := map[string]bool{}
+	for _, assignment := range set {
+		if len(assignment.Columns) != len(assignment.Values) {
+			return nil, nil, errors.New("values count does not match the columns count")
+		}
+		for i, ref := range assignment.Columns {
+			table, name, err := parser.resolveColumn(updated, ref)
+			if err != nil {
+				return nil, nil, err
+			}
+			if seen[table+"."+name] {
+				return nil, nil, fmt.Errorf("column %s is specified more than once", name)
+			}
+			seen[table+"."+name] = true
+			if err := parser.checkUpdatableColumn(table, name); err != nil {
+				return nil, nil, err
+			}
+
+			value := assignment.Values[i]
+			if err := parser.checkValue(value, table, name, scope); err != nil {
+				return nil, nil, err
+			}
+			columns = append(columns, name)
+			values = append(values, value)
+		}
+	}
+	return columns, values, nil
+}
+
+// checkLimit verifies that the count of a LIMIT or OFFSET clause is a non
+// negative integer.
+func (parser *SQLParser) checkLimit(clause string, limit Expr, params *paramSet) error {
+	switch limit := limit.(type) {
+	case nil:
+		return nil
+	case *Param:
+		return params.add(limit, "", "", IntegerType)
+	case *Literal:
+		if count, ok := limit.Value.(int64); ok && count >= 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %s must be a non negative integer", clause, limit)
+}
@@ -0,0 +1,93 @@
+package sqlParser
+
+import "strings"
+
+// DataType is the type family of a value. The semantic analysis compares
+// types by family, the precision and length of a column type are ignored.
+type DataType string
+
+const (
+	UnknownType   DataType = "UNKNOWN"
+	NullType      DataType = "NULL"
+	TextType      DataType = "TEXT"
+	IntegerType   DataType = "INTEGER"
+	DecimalType   DataType = "DECIMAL"
+	FloatType     DataType = "FLOAT"
+	BooleanType   DataType = "BOOLEAN"
+	DateType      DataType = "DATE"
+	TimeType      DataType = "TIME"
+	TimestampType DataType = "TIMESTAMP"
+	IntervalType  DataType = "INTERVAL"
+	BlobType      DataType = "BLOB"
+)
+
+// ParseDataType maps a SQL type name, as written in a schema, to its type
+// family: "varchar(255)" is TextType, "bigint" is IntegerType. Names it does
+// not know give UnknownType.
+func ParseDataType(sqlType string) DataType {
+	name := strings.ToUpper(strings.TrimSpace(sqlType))
+	// drop the length and precision: varchar(255), timestamp(3) with time zone
+	if open := strings.IndexByte(name, '('); open >= 0 {
+		if end := strings.IndexByte(name[open:], ')'); end >= 0 {
+			name = name[:open] + name[open+end+1:]
+		}
+	}
+	name = strings.Join(strings.Fields(name), " ")
+
+	switch name {
+	case "CHAR", "CHARACTER", "VARCHAR", "CHARACTER VARYING", "NCHAR", "NVARCHAR",
+		"TEXT", "TINYTEXT", "MEDIUMTEXT", "LONGTEXT", "NTEXT", "CLOB", "STRING", "UUID":
+		return TextType
+	case "INT", "INTEGER", "SMALLINT", "BIGINT", "TINYINT", "MEDIUMINT",
+		"INT2", "INT4", "INT8", "SERIAL", "SMALLSERIAL", "BIGSERIAL":
+		return IntegerType
+	case "DECIMAL", "NUMERIC", "DEC", "MONEY":
+		return DecimalType
+	case "FLOAT", "REAL", "DOUBLE", "DOUBLE PRECISION", "FLOAT4", "FLOAT8":
+		return FloatType
+	case "BOOL", "BOOLEAN":
+		return BooleanType
+	case "DATE":
+		return DateType
+	case "TIME", "TIME WITH TIME ZONE", "TIME WITHOUT TIME ZONE", "TIMETZ":
+		return TimeType
+	case "TIMESTAMP", "TIMESTAMP WITH TIME ZONE", "TIMESTAMP WITHOUT TIME ZONE", "TIMESTAMPTZ", "DATETIME":
+		return TimestampType
+	case "INTERVAL":
+		return IntervalType
+	case "BLOB", "BYTEA", "BINARY", "VARBINARY", "LONGBLOB":
+		return BlobType
+	}
+	return UnknownType
+}
+
+// numericRanks orders the numeric types from the narrowest to the widest.
+var numericRanks = map[DataType]int{IntegerType: 1, DecimalType: 2, FloatType: 3}
+
+// IsNumeric reports whether the type is a number type.
+func (t DataType) IsNumeric() bool {
+	return numericRanks[t] > 0
+}
+
+// commonType returns the type two values are converted to when they are
+// compared or mixed, ok is false when the types are incompatible. NULL and
+// unknown types convert to any type, numbers widen to the widest type and
+// dates widen to timestamps.
+func commonType(a, b DataType) (common DataType, ok bool) {
+	switch {
+	case a == b:
+		return a, true
+	case a == UnknownType || a == NullType:
+		return b, true
+	case b == UnknownType || b == NullType:
+		return a, true
+	case a.IsNumeric() && b.IsNumeric():
+		if numericRanks[a] > numericRanks[b] {
+			return a, true
+		}
+		return b, true
+	case (a == DateType && b == TimestampType) || (a == TimestampType && b == DateType):
+		return TimestampType, true
+	}
+	return UnknownType, false
+}
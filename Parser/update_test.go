@@ -0,0 +1,44 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpdate(t *testing.T) {
+	runParseTests(t, newTestParser, []parseTest{
+		{name: "expression", sql: "UPDATE users SET age = age + 1, name = UPPER(name) WHERE id = 1"},
+		{name: "row value", sql: "UPDATE users SET (name, age) = ('ann', 30) WHERE id = 1"},
+		{name: "from", sql: "UPDATE orders SET total = total * 2 FROM users WHERE orders.user_id = users.id AND users.age > 60"},
+		{name: "unknown column", sql: "UPDATE users SET nope = 1 WHERE id = 1", err: "column NOPE does not exist in table USERS"},
+		{name: "column of a from table", sql: "UPDATE users SET user_id = 1 FROM orders WHERE users.id = orders.user_id", err: "column USER_ID does not exist in table USERS"},
+		{name: "duplicate column", sql: "UPDATE users SET age = 1, age = 2 WHERE id = 1", err: "column AGE is specified more than once"},
+		{name: "row value width", sql: "UPDATE users SET (name, age) = ('ann') WHERE id = 1", err: "values count does not match the columns count"},
+		{name: "value type", sql: "UPDATE users SET age = 'old' WHERE id = 1", err: "does not match the type INTEGER of column AGE"},
+		{name: "unknown where column", sql: "UPDATE users SET age = 1 WHERE nope = 1", err: "column NOPE does not exist in table USERS"},
+		{name: "multiple tables", sql: "UPDATE users, orders SET users.age = 1 WHERE users.id = orders.user_id", err: "generic does not support multiple-table UPDATE statements"},
+		{name: "order by", sql: "UPDATE users SET age = 1 ORDER BY id LIMIT 1", err: "generic does not support ORDER BY in UPDATE statements"},
+		{name: "limit", sql: "UPDATE users SET age = 1 LIMIT 1", err: "generic does not support LIMIT in UPDATE statements"},
+	})
+	runParseTests(t, newDialectParser(MySQLDialect), []parseTest{
+		{name: "multiple tables", sql: "UPDATE users, orders SET users.age = 1, orders.total = 0 WHERE users.id = orders.user_id"},
+		{name: "order by and limit", sql: "UPDATE users SET age = 1 ORDER BY age DESC LIMIT 10"},
+		{name: "negative limit", sql: "UPDATE users SET age = 1 LIMIT -1", err: "LIMIT -1 must be a non negative integer"},
+		{name: "from", sql: "UPDATE orders SET total = 0 FROM users WHERE orders.user_id = users.id", err: "mysql does not support FROM in UPDATE statements"},
+	})
+}
+
+func TestUpdateStatement(t *testing.T) {
+	parser := newTestParser()
+	parser.Dialect = MySQLDialect
+	parsedStmt := mustParse(t, parser, "UPDATE users, orders SET users.age = 1, (total) = (0) WHERE users.id = orders.user_id")
+	if want := []string{"USERS", "ORDERS"}; !reflect.DeepEqual(parsedStmt.Tables, want) {
+		t.Fatalf("expected the tables %v, got %v", want, parsedStmt.Tables)
+	}
+	if want := []string{"AGE", "TOTAL"}; !reflect.DeepEqual(parsedStmt.Columns, want) {
+		t.Fatalf("expected the columns %v, got %v", want, parsedStmt.Columns)
+	}
+	if want := []string{"1", "0"}; !reflect.DeepEqual(parsedStmt.Values, want) {
+		t.Fatalf("expected the values %v, got %v", want, parsedStmt.Values)
+	}
+}
@@ -0,0 +1,99 @@
+package sqlParser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// InsertedValue is the VALUES(column) of ON DUPLICATE KEY UPDATE, the value
+// the row would have been inserted with.
+type InsertedValue struct {
+	Trivia
+	Column Identifier
+}
+
+func (value *InsertedValue) String() string {
+	return "VALUES(" + value.Column.String() + ")"
+}
+
+func (*InsertedValue) exprNode() {}
+
+// validateOnConflict checks the upsert clause of an INSERT into the table:
+// the conflict target must be a PRIMARY KEY or UNIQUE index of the table and
+// the SET columns, values and conditions must be valid for it.
+func (parser *SQLParser) validateOnConflict(stmt *InsertStmt, table string, params *paramSet, reads columnReads) error {
+	clause := stmt.OnConflict
+	switch {
+	case !clause.Constraint.IsEmpty():
+		if err := parser.validateConflictConstraint(clause.Constraint, table); err != nil {
+			return err
+		}
+	case len(clause.Target) > 0:
+		columns, err := parser.validateColumnExistence([]string{table}, columnRefs(clause.Target))
+		if err != nil {
+			return err
+		}
+		if valid, column := containsNoDuplicates(columns); !valid {
+			return fmt.Errorf("column %s is specified more than once in the conflict target", column)
+		}
+		if _, ok := parser.Schema.uniqueIndex(table, columns); !ok {
+			return fmt.Errorf("no PRIMARY KEY or UNIQUE index of table %s matches the conflict target (%s)", table, strings.Join(columns, ", "))
+		}
+	case clause.DuplicateKey:
+		if !parser.hasUniqueIndex(table) {
+			return fmt.Errorf("table %s has no PRIMARY KEY or UNIQUE index", table)
+		}
+	case !clause.DoNothing:
+		return errors.New("ON CONFLICT DO UPDATE requires a conflict target")
+	}
+	if clause.DoNothing {
+		return nil
+	}
+
+	// the SET values and conditions can read the proposed row
+	tables := []string{table}
+	scope := exprScope{tables: tables, params: params, reads: reads, excluded: table, excludedName: Identifier{Name: "EXCLUDED"}}
+	if clause.DuplicateKey {
+		scope.excludedName = stmt.RowAlias
+		scope.insertedValues = true
+		if !stmt.RowAlias.IsEmpty() && parser.Schema.matchesTable(Identifier{}, stmt.RowAlias, table) {
+			return fmt.Errorf("row alias %s can not be the name of table %s", stmt.RowAlias, table)
+		}
+	}
+	if _, _, err := parser.validateAssignments(clause.Set, tables, scope); err != nil {
+		return err
+	}
+
+	return parser.validateConditionExistence(clause.Where, scope)
+}
+
+// validateConflictConstraint checks that the index named by ON CONSTRAINT is
+// a PRIMARY KEY or UNIQUE index of the table.
+func (parser *SQLParser) validateConflictConstraint(constraint Identifier, table string) error {
+	indexes := parser.Schema.GetTableIndexes(table)
+	names := make([]string, 0, len(indexes))
+	for _, index := range indexes {
+		names = append(names, index.Name)
+	}
+	name, ok := constraint.resolve(names)
+	if !ok {
+		return fmt.Errorf("constraint %s does not exist on table %s", constraint, table)
+	}
+	for _, index := range indexes {
+		if index.Name == name && !index.Primary && !index.Unique {
+			return fmt.Errorf("constraint %s is not a PRIMARY KEY or UNIQUE index of table %s", name, table)
+		}
+	}
+	return nil
+}
+
+// hasUniqueIndex reports whether the table has a PRIMARY KEY or UNIQUE index.
+func (parser *SQLParser) hasUniqueIndex(table string) bool {
+	for _, index := range parser.Schema.GetTableIndexes(table) {
+		if index.Primary || index.Unique {
+			return true
+		}
+	}
+	return false
+}
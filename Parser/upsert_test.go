@@ -0,0 +1,76 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpsert(t *testing.T) {
+	runParseTests(t, newTestParser, []parseTest{
+		{name: "do nothing", sql: "INSERT INTO users (id) VALUES (1) ON CONFLICT DO NOTHING"},
+		{name: "do update", sql: "INSERT INTO users (id, name) VALUES (1, 'ann') ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name"},
+		{name: "do update where", sql: "INSERT INTO users (id, age) VALUES (1, 30) ON CONFLICT (id) DO UPDATE SET age = EXCLUDED.age WHERE users.age < EXCLUDED.age"},
+		{name: "unique index target", sql: "INSERT INTO users (id, email) VALUES (1, 'a@b.c') ON CONFLICT (email) DO UPDATE SET id = EXCLUDED.id"},
+		{name: "constraint target", sql: "INSERT INTO users (id) VALUES (1) ON CONFLICT ON CONSTRAINT users_pk DO NOTHING"},
+		{name: "duplicate key", sql: "INSERT INTO users (id, name) VALUES (1, 'ann') ON DUPLICATE KEY UPDATE name = VALUES(name)"},
+		{name: "row alias", sql: "INSERT INTO users (id, name) VALUES (1, 'ann') AS new ON DUPLICATE KEY UPDATE name = new.name"},
+		{name: "target without index", sql: "INSERT INTO users (id) VALUES (1) ON CONFLICT (name) DO NOTHING", err: "no PRIMARY KEY or UNIQUE index of table USERS matches the conflict target (NAME)"},
+		{name: "duplicate target column", sql: "INSERT INTO users (id) VALUES (1) ON CONFLICT (id, id) DO NOTHING", err: "column ID is specified more than once in the conflict target"},
+		{name: "unknown constraint", sql: "INSERT INTO users (id) VALUES (1) ON CONFLICT ON CONSTRAINT nope DO NOTHING", err: "constraint NOPE does not exist on table USERS"},
+		{name: "update without target", sql: "INSERT INTO users (id) VALUES (1) ON CONFLICT DO UPDATE SET name = 'x'", err: "ON CONFLICT DO UPDATE requires a conflict target"},
+		{name: "unknown set column", sql: "INSERT INTO users (id) VALUES (1) ON CONFLICT (id) DO UPDATE SET nope = 1", err: "column NOPE does not exist in table USERS"},
+		{name: "set type", sql: "INSERT INTO users (id) VALUES (1) ON CONFLICT (id) DO UPDATE SET age = 'old'", err: "does not match the type INTEGER of column AGE"},
+		{name: "excluded outside upsert", sql: "UPDATE users SET name = EXCLUDED.name WHERE id = 1", err: "table EXCLUDED of column EXCLUDED.NAME is not part of the statement"},
+		{name: "values outside upsert", sql: "UPDATE users SET name = VALUES(name) WHERE id = 1", err: "VALUES(NAME) can only be used in ON DUPLICATE KEY UPDATE"},
+		{name: "excluded in duplicate key", sql: "INSERT INTO users (id) VALUES (1) ON DUPLICATE KEY UPDATE name = EXCLUDED.name", err: "table EXCLUDED of column EXCLUDED.NAME is not part of the statement"},
+		{name: "row alias naming the table", sql: "INSERT INTO users (id) VALUES (1) AS users ON DUPLICATE KEY UPDATE age = 1", err: "row alias USERS can not be the name of table USERS"},
+		{name: "missing action", sql: "INSERT INTO users (id) VALUES (1) ON CONFLICT (id) DO", err: "expected NOTHING or UPDATE"},
+	})
+
+	withLogs := func() *SQLParser {
+		parser := newTestParser()
+		parser.Schema.Tables["LOGS"] = []string{"LINE"}
+		return parser
+	}
+	runParseTests(t, withLogs, []parseTest{
+		{name: "duplicate key without unique index", sql: "INSERT INTO logs (line) VALUES ('a') ON DUPLICATE KEY UPDATE line = 'b'", err: "table LOGS has no PRIMARY KEY or UNIQUE index"},
+	})
+}
+
+func TestUpsertDialects(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		sql     string
+		err     string
+	}{
+		{dialect: PostgresDialect, sql: "INSERT INTO users (id) VALUES (1) ON CONFLICT (id) DO NOTHING"},
+		{dialect: SQLiteDialect, sql: "INSERT INTO users (id) VALUES (1) ON CONFLICT (id) DO NOTHING"},
+		{dialect: MySQLDialect, sql: "INSERT INTO users (id) VALUES (1) ON DUPLICATE KEY UPDATE name = 'x'"},
+		{dialect: PostgresDialect, sql: "INSERT INTO users (id) VALUES (1) ON DUPLICATE KEY UPDATE name = 'x'", err: "postgres does not support ON DUPLICATE KEY UPDATE"},
+		{dialect: MySQLDialect, sql: "INSERT INTO users (id) VALUES (1) ON CONFLICT (id) DO NOTHING", err: "mysql does not support ON CONFLICT"},
+		{dialect: SQLServerDialect, sql: "INSERT INTO users (id) VALUES (1) ON CONFLICT (id) DO NOTHING", err: "sqlserver does not support ON CONFLICT"},
+	}
+	for _, test := range tests {
+		t.Run(test.dialect.Name, func(t *testing.T) {
+			parser := newTestParser()
+			parser.Dialect = test.dialect
+			_, err := parser.ParseSQL(test.sql)
+			checkError(t, err, test.err)
+		})
+	}
+}
+
+func TestUpsertStatement(t *testing.T) {
+	parsedStmt := mustParse(t, newTestParser(), "INSERT INTO users (id, name) VALUES (1, 'ann') AS new ON DUPLICATE KEY UPDATE name = new.name, age = VALUES(age) + 1")
+	stmt := parsedStmt.Stmt.(*InsertStmt)
+	if stmt.RowAlias.Name != "NEW" || !stmt.OnConflict.DuplicateKey {
+		t.Fatalf("unexpected upsert: %+v", stmt)
+	}
+	var set []string
+	for _, assignment := range stmt.OnConflict.Set {
+		set = append(set, assignment.String())
+	}
+	if want := []string{"NAME = NEW.NAME", "AGE = VALUES(AGE) + 1"}; !reflect.DeepEqual(set, want) {
+		t.Fatalf("expected the assignments %q, got %q", want, set)
+	}
+}
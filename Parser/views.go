@@ -0,0 +1,136 @@
+package sqlParser
+
+import (
+	"errors"
+	"fmt"
+)
+
+// validateCreateView checks a CREATE VIEW statement and returns the view it
+// defines: the columns of the view are the result of its query, renamed
+// after the column list of the statement.
+func (parser *SQLParser) validateCreateView(stmt *CreateView) (View, error) {
+	schemaName, namespace, err := parser.Schema.creationSchema(stmt.Name)
+	if err != nil {
+		return View{}, err
+	}
+	name := parser.Schema.qualify(schemaName, stmt.Name.Name.Name)
+	if table, ok := stmt.Name.Name.resolve(namespace.GetSchemaTables()); ok {
+		return View{}, fmt.Errorf("table %s already exists", parser.Schema.qualify(schemaName, table))
+	}
+	if view, ok := stmt.Name.Name.resolve(namespace.GetSchemaViews()); ok {
+		name = parser.Schema.qualify(schemaName, view)
+		if !stmt.OrReplace {
+			return View{}, fmt.Errorf("view %s already exists", name)
+		}
+	}
+
+	query, err := parser.semanticAnalysis(ParsedStmt{QueryType: SelectQuery, Stmt: stmt.Select})
+	if err != nil {
+		return View{}, err
+	}
+	if len(query.Params) > 0 {
+		return View{}, errors.New("the query of a view can not use bind parameters")
+	}
+	if len(stmt.Columns) > len(query.Result) {
+		return View{}, fmt.Errorf("view %s names %d columns but its query returns %d columns", name, len(stmt.Columns), len(query.Result))
+	}
+
+	columns := append([]ResultColumn(nil), query.Result...)
+	names := make([]string, 0, len(columns))
+	for i := range columns {
+		if i < len(stmt.Columns) {
+			columns[i].Name = stmt.Columns[i].Name
+		}
+		names = append(names, columns[i].Name)
+	}
+	if valid, column := containsNoDuplicates(names); !valid {
+		return View{}, fmt.Errorf("column %s is specified more than once in view %s", column, name)
+	}
+	return View{Name: name, Columns: columns, Query: stmt.Select, Tables: query.Tables}, nil
+}
+
+// validateDropView checks that the view of a DROP VIEW statement exists and
+// that no other view reads it, and returns its name as stored in the schema.
+func (parser *SQLParser) validateDropView(view TableName) (string, error) {
+	name, err := parser.Schema.LookupTable(view)
+	if err != nil || !parser.Schema.IsView(name) {
+		if !view.Schema.IsEmpty() {
+			if _, err := parser.Schema.LookupSchema(view.Schema); err != nil {
+				return "", err
+			}
+		}
+		return "", fmt.Errorf("view %s does not exist in the schema", view.Name)
+	}
+	if dependent, ok := parser.dependentView(name); ok {
+		return "", fmt.Errorf("can not drop view %s because view %s depends on it", name, dependent)
+	}
+	return name, nil
+}
+
+// checkUpdatableView rejects the INSERT, UPDATE and DELETE statements
+// writing to a view that is not updatable. Each row of an updatable view is a
+// row of the single table or updatable view its query reads, which rules out
+// DISTINCT, GROUP BY, aggregate and window functions, LIMIT and OFFSET.
+func (parser *SQLParser) checkUpdatableView(name string) error {
+	view, ok := parser.Schema.LookupView(name)
+	if !ok || view.Query == nil {
+		return nil
+	}
+	query := view.Query
+	reason := ""
+	switch {
+	case len(query.Tables) != 1:
+		reason = "its query does not read exactly one table"
+	case query.Distinct:
+		reason = "its query uses DISTINCT"
+	case len(query.GroupBy) > 0:
+		reason = "its query uses GROUP BY"
+	case query.Limit != nil || query.Offset != nil:
+		reason = "its query uses LIMIT or OFFSET"
+	}
+	for _, item := range query.Columns {
+		walkExpr(item.Expr, func(expr Expr) bool {
+			if call, ok := expr.(*FuncCall); ok && reason == "" && (call.Over != nil || parser.isAggregate(call)) {
+				reason = fmt.Sprintf("its query calls the aggregate or window function %s", call.Name)
+			}
+			return reason == ""
+		})
+	}
+	if reason != "" {
+		return fmt.Errorf("view %s is not updatable, %s", name, reason)
+	}
+	if len(view.Tables) == 0 {
+		return nil
+	}
+	return parser.checkUpdatableView(view.Tables[0])
+}
+
+// checkUpdatableColumn rejects the INSERT and UPDATE statements writing to a
+// column of a view that is computed by its query, rather than read from a
+// column of its table.
+func (parser *SQLParser) checkUpdatableColumn(table, column string) error {
+	view, ok := parser.Schema.LookupView(table)
+	if !ok {
+		return nil
+	}
+	for _, result := range view.Columns {
+		if result.Name != column {
+			continue
+		}
+		if result.Column == "" {
+			return fmt.Errorf("column %s of view %s is not updatable, it is computed", column, table)
+		}
+		return parser.checkUpdatableColumn(result.Table, result.Column)
+	}
+	return nil
+}
+
+// dependentView returns a view whose query reads the table or view.
+func (parser *SQLParser) dependentView(name string) (string, bool) {
+	for _, view := range parser.Schema.views() {
+		if containsString(view.Tables, name) {
+			return view.Name, true
+		}
+	}
+	return "", false
+}
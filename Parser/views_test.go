@@ -0,0 +1,94 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newViewParser returns a parser on the test schema with a few views created:
+// ADULTS and NAMED are updatable, the others are not.
+func newViewParser() *SQLParser {
+	parser := newTestParser()
+	for _, sql := range []string{
+		"CREATE VIEW adults AS SELECT id, name, age FROM users WHERE age >= 18",
+		"CREATE VIEW named (who, years) AS SELECT name, age + 0 FROM adults",
+		"CREATE VIEW totals AS SELECT user_id, SUM(total) FROM orders GROUP BY user_id",
+		"CREATE VIEW recent AS SELECT id, total FROM orders ORDER BY created_at DESC LIMIT 10",
+		"CREATE VIEW buyers AS SELECT DISTINCT user_id FROM orders",
+		"CREATE VIEW ranked AS SELECT id, RANK() OVER (ORDER BY total) FROM orders",
+	} {
+		if _, err := parser.ParseSQL(sql); err != nil {
+			panic(err)
+		}
+	}
+	return parser
+}
+
+func TestViews(t *testing.T) {
+	runParseTests(t, newViewParser, []parseTest{
+		{name: "create", sql: "CREATE VIEW big_orders AS SELECT id, total FROM orders WHERE total > 100"},
+		{name: "create with columns", sql: "CREATE VIEW names (n) AS SELECT name FROM users"},
+		{name: "create or replace", sql: "CREATE OR REPLACE VIEW adults AS SELECT id, name FROM users WHERE age > 21"},
+		{name: "view of a view", sql: "CREATE VIEW old AS SELECT who FROM named WHERE years > 60"},
+		{name: "select from a view", sql: "SELECT who, years FROM named ORDER BY who"},
+		{name: "star of a view", sql: "SELECT * FROM adults"},
+		{name: "drop", sql: "DROP VIEW totals"},
+		{name: "existing view", sql: "CREATE VIEW adults AS SELECT id FROM users", err: "view ADULTS already exists"},
+		{name: "existing table", sql: "CREATE VIEW users AS SELECT id FROM orders", err: "table USERS already exists"},
+		{name: "too many columns", sql: "CREATE VIEW v (a, b) AS SELECT id FROM users", err: "view V names 2 columns but its query returns 1 columns"},
+		{name: "duplicate column", sql: "CREATE VIEW v AS SELECT id, id FROM users", err: "column ID is specified more than once in view V"},
+		{name: "duplicate renamed column", sql: "CREATE VIEW v (a, a) AS SELECT id, name FROM users", err: "column A is specified more than once in view V"},
+		{name: "bind parameter", sql: "CREATE VIEW v AS SELECT id FROM users WHERE age > $1", err: "the query of a view can not use bind parameters"},
+		{name: "invalid query", sql: "CREATE VIEW v AS SELECT nope FROM users", err: "column NOPE does not exist"},
+		{name: "unknown column of a view", sql: "SELECT email FROM adults", err: "column EMAIL does not exist"},
+		{name: "drop missing view", sql: "DROP VIEW nope", err: "view NOPE does not exist in the schema"},
+		{name: "drop a table as a view", sql: "DROP VIEW users", err: "view USERS does not exist in the schema"},
+		{name: "drop a view read by another", sql: "DROP VIEW adults", err: "can not drop view ADULTS because view NAMED depends on it"},
+	})
+}
+
+func TestUpdatableViews(t *testing.T) {
+	runParseTests(t, newViewParser, []parseTest{
+		{name: "insert", sql: "INSERT INTO adults (id, name, age) VALUES (1, 'ada', 36)"},
+		{name: "update", sql: "UPDATE adults SET name = 'bob' WHERE id = 1"},
+		{name: "delete", sql: "DELETE FROM adults WHERE age > 90"},
+		{name: "update through a view of a view", sql: "UPDATE named SET who = 'bob' WHERE years = 40"},
+		{name: "delete through a view of a view", sql: "DELETE FROM named WHERE who = 'bob'"},
+		{name: "computed column", sql: "UPDATE named SET years = 40", err: "column YEARS of view NAMED is not updatable, it is computed"},
+		{name: "insert into a computed column", sql: "INSERT INTO named (who, years) VALUES ('ada', 36)", err: "column YEARS of view NAMED is not updatable, it is computed"},
+		{name: "group by", sql: "DELETE FROM totals", err: "view TOTALS is not updatable, its query uses GROUP BY"},
+		{name: "limit", sql: "UPDATE recent SET total = 0", err: "view RECENT is not updatable, its query uses LIMIT or OFFSET"},
+		{name: "distinct", sql: "INSERT INTO buyers (user_id) VALUES (1)", err: "view BUYERS is not updatable, its query uses DISTINCT"},
+		{name: "window function", sql: "DELETE FROM ranked WHERE id = 1", err: "view RANKED is not updatable, its query calls the aggregate or window function RANK"},
+	})
+}
+
+func TestCatalogChangesLeaveCallerSchema(t *testing.T) {
+	schema := testSchema()
+	schema.Views = map[string]View{}
+	schema.Schemas = map[string]*Schema{"SALES": {Name: "SALES", Tables: map[string][]string{}}}
+	parser := NewSQLParser(schema)
+	for _, sql := range []string{
+		"CREATE VIEW adults AS SELECT id FROM users WHERE age >= 18",
+		"CREATE TABLE sales.notes (id)",
+		"CREATE INDEX users_name ON users (name)",
+		"DROP INDEX users_email",
+		"CREATE SCHEMA hr",
+	} {
+		mustParse(t, parser, sql)
+	}
+
+	want := testSchema()
+	if len(schema.Views) != 0 {
+		t.Errorf("expected the caller's views to be left empty, got %v", schema.Views)
+	}
+	if len(schema.Schemas) != 1 || len(schema.Schemas["SALES"].Tables) != 0 {
+		t.Errorf("expected the caller's schemas to be left as they were, got %v", schema.Schemas)
+	}
+	if !reflect.DeepEqual(schema.Indexes, want.Indexes) {
+		t.Errorf("expected the caller's indexes %v, got %v", want.Indexes, schema.Indexes)
+	}
+	if _, ok := parser.Schema.LookupView("ADULTS"); !ok {
+		t.Error("expected the parser's schema to hold the view")
+	}
+}
@@ -0,0 +1,234 @@
+package sqlParser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WindowSpec is the window a window function is computed over: the
+// OVER (...) of a call or the definition of a WINDOW clause.
+type WindowSpec struct {
+	Trivia
+	Ref         Identifier // OVER name, the other fields are then empty
+	Base        Identifier // the window the specification extends: (name ORDER BY ...)
+	PartitionBy []Expr
+	OrderBy     []OrderItem
+	Frame       *WindowFrame // nil without frame clause
+}
+
+func (spec *WindowSpec) String() string {
+	if !spec.Ref.IsEmpty() {
+		return spec.Ref.String()
+	}
+	var parts []string
+	if !spec.Base.IsEmpty() {
+		parts = append(parts, spec.Base.String())
+	}
+	if len(spec.PartitionBy) > 0 {
+		parts = append(parts, "PARTITION BY "+strings.Join(exprStrings(spec.PartitionBy), ", "))
+	}
+	if len(spec.OrderBy) > 0 {
+		items := make([]string, 0, len(spec.OrderBy))
+		for _, item := range spec.OrderBy {
+			items = append(items, item.String())
+		}
+		parts = append(parts, "ORDER BY "+strings.Join(items, ", "))
+	}
+	if spec.Frame != nil {
+		parts = append(parts, spec.Frame.String())
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// WindowFrame is the frame clause of a window, the rows of the partition
+// the function sees for the current row:
+// ROWS | RANGE | GROUPS [BETWEEN] start [AND end] [EXCLUDE ...]
+type WindowFrame struct {
+	Unit    string // ROWS, RANGE or GROUPS
+	Start   FrameBound
+	End     *FrameBound // nil without BETWEEN, the frame then ends at the current row
+	Exclude string      // CURRENT ROW, GROUP, TIES or NO OTHERS, empty without EXCLUDE
+}
+
+func (frame *WindowFrame) String() string {
+	s := frame.Unit + " " + frame.Start.String()
+	if frame.End != nil {
+		s = frame.Unit + " BETWEEN " + frame.Start.String() + " AND " + frame.End.String()
+	}
+	if frame.Exclude != "" {
+		s += " EXCLUDE " + frame.Exclude
+	}
+	return s
+}
+
+// BoundType tells where a frame starts or ends.
+type BoundType string
+
+const (
+	UnboundedPreceding BoundType = "UNBOUNDED PRECEDING"
+	Preceding          BoundType = "PRECEDING"
+	CurrentRow         BoundType = "CURRENT ROW"
+	Following          BoundType = "FOLLOWING"
+	UnboundedFollowing BoundType = "UNBOUNDED FOLLOWING"
+)
+
+// boundOrder ranks the bound types from the start of the partition to its
+// end.
+var boundOrder = map[BoundType]int{
+	UnboundedPreceding: 0,
+	Preceding:          1,
+	CurrentRow:         2,
+	Following:          3,
+	UnboundedFollowing: 4,
+}
+
+// FrameBound is the start or the end of a window frame.
+type FrameBound struct {
+	Type   BoundType
+	Offset Expr // the offset of PRECEDING and FOLLOWING bounds: 3 PRECEDING
+}
+
+func (bound FrameBound) String() string {
+	if bound.Offset == nil {
+		return string(bound.Type)
+	}
+	return bound.Offset.String() + " " + string(bound.Type)
+}
+
+// NamedWindow is a window defined by the WINDOW clause of a SELECT:
+// WINDOW name AS (...)
+type NamedWindow struct {
+	Trivia
+	Name Identifier
+	Spec *WindowSpec
+}
+
+func (window NamedWindow) String() string {
+	return window.Name.String() + " AS " + window.Spec.String()
+}
+
+// validateWindows checks the windows of the WINDOW clause of a SELECT, each
+// one may extend the windows defined before it.
+func (parser *SQLParser) validateWindows(windows []NamedWindow, scope exprScope) error {
+	for i, window := range windows {
+		for _, defined := range windows[:i] {
+			if window.Name.Matches(defined.Name.Name) {
+				return fmt.Errorf("window %s is already defined", window.Name)
+			}
+		}
+		scope.namedWindows = windows[:i]
+		if err := parser.validateWindow(window.Spec, scope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupWindow returns the window of the WINDOW clause of the given name.
+func (scope exprScope) lookupWindow(name Identifier) (*WindowSpec, error) {
+	for _, window := range scope.namedWindows {
+		if name.Matches(window.Name.Name) {
+			return window.Spec, nil
+		}
+	}
+	return nil, fmt.Errorf("window %s does not exist", name)
+}
+
+// validateWindow checks the window of a window function. A window extending
+// another one can only add the clauses the other one lacks, and a window
+// with a frame can only be referred to by OVER name.
+func (parser *SQLParser) validateWindow(spec *WindowSpec, scope exprScope) error {
+	if !spec.Ref.IsEmpty() {
+		_, err := scope.lookupWindow(spec.Ref)
+		return err
+	}
+
+	orderBy := spec.OrderBy
+	if !spec.Base.IsEmpty() {
+		base, err := scope.lookupWindow(spec.Base)
+		if err != nil {
+			return err
+		}
+		switch {
+		case len(spec.PartitionBy) > 0:
+			return fmt.Errorf("can not override the PARTITION BY clause of window %s", spec.Base)
+		case len(spec.OrderBy) > 0 && len(base.OrderBy) > 0:
+			return fmt.Errorf("can not override the ORDER BY clause of window %s", spec.Base)
+		case base.Frame != nil:
+			return fmt.Errorf("can not copy window %s because it has a frame clause", spec.Base)
+		}
+		if len(orderBy) == 0 {
+			orderBy = base.OrderBy
+		}
+	}
+
+	// the window is computed before the window functions
+	scope.windows = false
+	for _, expr := range spec.PartitionBy {
+		if _, err := parser.exprType(expr, scope); err != nil {
+			return err
+		}
+	}
+	for _, item := range spec.OrderBy {
+		if _, err := parser.exprType(item.Expr, scope); err != nil {
+			return err
+		}
+	}
+	if spec.Frame == nil {
+		return nil
+	}
+	return parser.validateFrame(spec.Frame, orderBy, scope)
+}
+
+// validateFrame checks the bounds of a window frame: the frame can not
+// start after its end, and the offsets count rows or groups of rows, or are
+// a distance from the value of the single ORDER BY column in RANGE mode.
+func (parser *SQLParser) validateFrame(frame *WindowFrame, orderBy []OrderItem, scope exprScope) error {
+	end := FrameBound{Type: CurrentRow}
+	if frame.End != nil {
+		end = *frame.End
+	}
+	switch {
+	case frame.Start.Type == UnboundedFollowing:
+		return fmt.Errorf("frame start can not be %s", UnboundedFollowing)
+	case end.Type == UnboundedPreceding:
+		return fmt.Errorf("frame end can not be %s", UnboundedPreceding)
+	case boundOrder[frame.Start.Type] > boundOrder[end.Type]:
+		return fmt.Errorf("frame starting from %s can not end with %s", frame.Start.Type, end.Type)
+	case frame.Unit == "GROUPS" && len(orderBy) == 0:
+		return errors.New("GROUPS mode requires an ORDER BY clause")
+	}
+
+	for _, bound := range []FrameBound{frame.Start, end} {
+		if bound.Offset == nil {
+			continue
+		}
+		if frame.Unit != "RANGE" {
+			if err := parser.checkLimit("frame offset", bound.Offset, scope.params); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(orderBy) != 1 {
+			return fmt.Errorf("RANGE with offset %s requires exactly one ORDER BY column", bound.Type)
+		}
+		if _, err := parser.exprType(bound.Offset, scope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lagType returns the type of LAG and LEAD, the type of their first
+// argument, which the default value must convert to.
+func lagType(args []DataType) (DataType, error) {
+	if len(args) < 3 {
+		return args[0], nil
+	}
+	typ, ok := commonType(args[0], args[2])
+	if !ok {
+		return UnknownType, fmt.Errorf("default value of type %s does not match the type %s", args[2], args[0])
+	}
+	return typ, nil
+}
@@ -0,0 +1,62 @@
+package sqlParser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWindowFunctions(t *testing.T) {
+	runParseTests(t, newTestParser, []parseTest{
+		{name: "ranking", sql: "SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at DESC) FROM orders"},
+		{name: "aggregate over a window", sql: "SELECT id, SUM(total) OVER (PARTITION BY user_id) FROM orders"},
+		{name: "empty window", sql: "SELECT COUNT(*) OVER () FROM orders"},
+		{name: "named window", sql: "SELECT RANK() OVER w, LAG(total, 1, 0) OVER w FROM orders WINDOW w AS (PARTITION BY user_id ORDER BY total)"},
+		{name: "window copying another", sql: "SELECT SUM(total) OVER (w ROWS BETWEEN 1 PRECEDING AND CURRENT ROW) FROM orders WINDOW w AS (ORDER BY created_at)"},
+		{name: "frame", sql: "SELECT SUM(total) OVER (ORDER BY id ROWS BETWEEN UNBOUNDED PRECEDING AND 1 FOLLOWING) FROM orders"},
+		{name: "ordered by a window function", sql: "SELECT id FROM orders ORDER BY ROW_NUMBER() OVER (ORDER BY total)"},
+		{name: "without over", sql: "SELECT ROW_NUMBER() FROM orders", err: "window function ROW_NUMBER requires an OVER clause"},
+		{name: "not a window function", sql: "SELECT UPPER(name) OVER () FROM users", err: "UPPER is not a window or aggregate function"},
+		{name: "window function in where", sql: "SELECT id FROM orders WHERE RANK() OVER (ORDER BY id) = 1", err: "window function RANK is not allowed here"},
+		{name: "unknown window", sql: "SELECT RANK() OVER w FROM orders", err: "window W does not exist"},
+		{name: "window defined twice", sql: "SELECT RANK() OVER w FROM orders WINDOW w AS (), w AS ()", err: "window W is already defined"},
+		{name: "overridden partition", sql: "SELECT RANK() OVER (w PARTITION BY id) FROM orders WINDOW w AS (PARTITION BY user_id)", err: "can not override the PARTITION BY clause of window W"},
+		{name: "overridden order", sql: "SELECT RANK() OVER (w ORDER BY id) FROM orders WINDOW w AS (ORDER BY user_id)", err: "can not override the ORDER BY clause of window W"},
+		{name: "copied frame", sql: "SELECT RANK() OVER (w) FROM orders WINDOW w AS (ORDER BY id ROWS UNBOUNDED PRECEDING)", err: "can not copy window W because it has a frame clause"},
+		{name: "frame start", sql: "SELECT SUM(total) OVER (ORDER BY id ROWS UNBOUNDED FOLLOWING) FROM orders", err: "frame start can not be UNBOUNDED FOLLOWING"},
+		{name: "groups without order", sql: "SELECT SUM(total) OVER (GROUPS 1 PRECEDING) FROM orders", err: "GROUPS mode requires an ORDER BY clause"},
+		{name: "range offset", sql: "SELECT SUM(total) OVER (ORDER BY id, total RANGE 1 PRECEDING) FROM orders", err: "RANGE with offset PRECEDING requires exactly one ORDER BY column"},
+		{name: "lag default type", sql: "SELECT LAG(total, 1, 'none') OVER (ORDER BY id) FROM orders", err: "default value of type TEXT does not match the type DECIMAL"},
+	})
+}
+
+func TestWindowFunctionTypes(t *testing.T) {
+	parsedStmt := mustParse(t, newTestParser(), "SELECT ROW_NUMBER() OVER w, SUM(total) OVER w, LAG(total) OVER w, COUNT(*) OVER () FROM orders WINDOW w AS (ORDER BY id)")
+	var types []DataType
+	for _, column := range parsedStmt.Result {
+		types = append(types, column.Type)
+	}
+	want := []DataType{IntegerType, DecimalType, DecimalType, IntegerType}
+	if !reflect.DeepEqual(types, want) {
+		t.Fatalf("expected the result types %v, got %v", want, types)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	runParseTests(t, newTestParser, []parseTest{
+		{name: "grouped column", sql: "SELECT user_id, COUNT(*), SUM(total) FROM orders GROUP BY user_id"},
+		{name: "grouped expression", sql: "SELECT UPPER(name), MAX(age) + 1 FROM users GROUP BY UPPER(name)"},
+		{name: "expression of grouped columns", sql: "SELECT UPPER(name) FROM users GROUP BY name"},
+		{name: "group by position", sql: "SELECT user_id, COUNT(*) FROM orders GROUP BY 1"},
+		{name: "group by alias", sql: "SELECT user_id AS buyer, COUNT(*) FROM orders GROUP BY buyer ORDER BY buyer"},
+		{name: "aggregate without group by", sql: "SELECT COUNT(*), MAX(total) FROM orders"},
+		{name: "ordered by an aggregate", sql: "SELECT user_id FROM orders GROUP BY user_id ORDER BY COUNT(*) DESC"},
+		{name: "window over groups", sql: "SELECT user_id, RANK() OVER (ORDER BY SUM(total)) FROM orders GROUP BY user_id"},
+		{name: "ungrouped column", sql: "SELECT total FROM orders GROUP BY user_id", err: "column TOTAL must appear in the GROUP BY clause or be used in an aggregate function"},
+		{name: "column next to an aggregate", sql: "SELECT name, COUNT(*) FROM users", err: "column NAME must appear in the GROUP BY clause or be used in an aggregate function"},
+		{name: "ungrouped order by", sql: "SELECT user_id FROM orders GROUP BY user_id ORDER BY total", err: "column TOTAL must appear in the GROUP BY clause"},
+		{name: "ungrouped window argument", sql: "SELECT user_id, RANK() OVER (ORDER BY total) FROM orders GROUP BY user_id", err: "column TOTAL must appear in the GROUP BY clause"},
+		{name: "aggregate in where", sql: "SELECT user_id FROM orders WHERE COUNT(*) > 1 GROUP BY user_id", err: "aggregate function COUNT is not allowed here"},
+		{name: "aggregate in group by", sql: "SELECT COUNT(*) FROM orders GROUP BY COUNT(*)", err: "aggregate function COUNT is not allowed here"},
+		{name: "group by position out of the list", sql: "SELECT user_id FROM orders GROUP BY 2", err: "GROUP BY position 2 is not in select list"},
+	})
+}